@@ -0,0 +1,179 @@
+package logging
+
+import (
+    "io"
+    "sync"
+    "sync/atomic"
+
+    "github.com/pkg/errors"
+)
+
+// OverflowPolicy decides what an AsyncWriter does with a write when its queue
+// is full.
+type OverflowPolicy int
+
+// Definition of the available OverflowPolicies.
+const (
+    // Block waits for room in the queue.
+    Block OverflowPolicy = iota
+    // DropNewest drops the write that didn't fit.
+    DropNewest
+    // DropOldest drops the oldest queued write to make room.
+    DropOldest
+)
+
+// DefaultAsyncQueueSize is the queue size used for an AsyncWriter if the size
+// provided isn't positive.
+const DefaultAsyncQueueSize = 1024
+
+// ErrAsyncWriterClosed is returned when writing to an AsyncWriter which has
+// been closed.
+var ErrAsyncWriterClosed = errors.New("AsyncWriter is closed")
+
+// AsyncWriter wraps a writer so that writes are queued and written by a
+// background goroutine rather than by the goroutine logging. It should be
+// closed when it's no longer needed to make sure every queued write is
+// written.
+type AsyncWriter struct {
+    writer io.Writer
+    policy OverflowPolicy
+    queue chan []byte
+    flushRequests chan chan error
+    done chan struct{}
+    // dropped is only accessed atomically.
+    dropped uint64
+
+    // closeRWMutex stops the queue being closed while a write or flush is in
+    // progress.
+    closeRWMutex sync.RWMutex
+    closed bool
+}
+
+// NewAsyncWriter wraps writer in an AsyncWriter which can hold queueSize
+// writes and uses policy when the queue is full.
+func NewAsyncWriter(
+    writer io.Writer, queueSize int, policy OverflowPolicy,
+) *AsyncWriter {
+    if queueSize < 1 {
+        queueSize = DefaultAsyncQueueSize
+    }
+
+    asyncWriter := &AsyncWriter{
+        writer: writer,
+        policy: policy,
+        queue: make(chan []byte, queueSize),
+        flushRequests: make(chan chan error),
+        done: make(chan struct{}),
+    }
+    go asyncWriter.run()
+
+    return asyncWriter
+}
+
+func (self *AsyncWriter) run() {
+    defer close(self.done)
+    for {
+        select {
+        case p, ok := <-self.queue:
+            if !ok {
+                return
+            }
+            _, _ = self.writer.Write(p)
+        case flushed := <-self.flushRequests:
+            self.drain()
+            flushed <- flushWriter(self.writer)
+        }
+    }
+}
+
+// drain writes everything currently in the queue.
+func (self *AsyncWriter) drain() {
+    for {
+        select {
+        case p := <-self.queue:
+            _, _ = self.writer.Write(p)
+        default:
+            return
+        }
+    }
+}
+
+// Write queues a copy of p to be written. It never returns an error unless
+// the AsyncWriter has been closed; writes dropped because the queue is full
+// are counted by Dropped instead.
+func (self *AsyncWriter) Write(p []byte) (int, error) {
+    self.closeRWMutex.RLock()
+    defer self.closeRWMutex.RUnlock()
+    if self.closed {
+        return 0, ErrAsyncWriterClosed
+    }
+
+    // NOTE: The caller is free to reuse p once Write returns.
+    queued := make([]byte, len(p))
+    copy(queued, p)
+
+    switch self.policy {
+    case DropNewest:
+        select {
+        case self.queue <- queued:
+        default:
+            atomic.AddUint64(&self.dropped, 1)
+        }
+    case DropOldest:
+        for {
+            select {
+            case self.queue <- queued:
+                return len(p), nil
+            default:
+            }
+
+            select {
+            case <-self.queue:
+                atomic.AddUint64(&self.dropped, 1)
+            default:
+            }
+        }
+    default:
+        self.queue <- queued
+    }
+
+    return len(p), nil
+}
+
+// Dropped gets the number of writes which have been dropped because the
+// queue was full.
+func (self *AsyncWriter) Dropped() uint64 {
+    return atomic.LoadUint64(&self.dropped)
+}
+
+// Flush blocks until every write queued before it was called has been
+// written and then flushes the wrapped writer if it supports it.
+func (self *AsyncWriter) Flush() error {
+    self.closeRWMutex.RLock()
+    defer self.closeRWMutex.RUnlock()
+    if self.closed {
+        return nil
+    }
+
+    flushed := make(chan error, 1)
+    self.flushRequests <- flushed
+
+    return <-flushed
+}
+
+// Close stops accepting writes and blocks until everything queued has been
+// written. The wrapped writer is flushed but not closed.
+func (self *AsyncWriter) Close() error {
+    self.closeRWMutex.Lock()
+    if self.closed {
+        self.closeRWMutex.Unlock()
+        return nil
+    }
+    self.closed = true
+    close(self.queue)
+    self.closeRWMutex.Unlock()
+
+    <-self.done
+
+    return flushWriter(self.writer)
+}
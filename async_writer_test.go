@@ -0,0 +1,134 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "math/rand"
+    "strconv"
+    "strings"
+    "sync"
+    "testing"
+
+    gm "github.com/onsi/gomega"
+)
+
+// blockingWriter blocks every write until it is released.
+type blockingWriter struct {
+    lockedBuilder
+    release chan struct{}
+    started chan struct{}
+    startOnce sync.Once
+}
+
+func newBlockingWriter() *blockingWriter {
+    return &blockingWriter{
+        release: make(chan struct{}),
+        started: make(chan struct{}),
+    }
+}
+
+func (self *blockingWriter) Write(p []byte) (int, error) {
+    self.startOnce.Do(func() {
+        close(self.started)
+    })
+    <-self.release
+
+    return self.lockedBuilder.Write(p)
+}
+
+func TestAsyncWriter(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    builder := new(lockedBuilder)
+    asyncWriter := NewAsyncWriter(builder, 10, Block)
+    defer asyncWriter.Close()
+
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(asyncWriter),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    for i := 0; i < 100; i++ {
+        newLogger.Info("Foo", Extras{"i": i})
+    }
+
+    err = asyncWriter.Flush()
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    lines := strings.Split(strings.TrimSpace(builder.String()), "\n")
+    g.Expect(lines).To(gm.HaveLen(100))
+    g.Expect(lines[99]).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo","i":99}`,
+    ))
+    g.Expect(asyncWriter.Dropped()).To(gm.BeZero())
+}
+
+func TestAsyncWriterDropNewest(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    writer := newBlockingWriter()
+    asyncWriter := NewAsyncWriter(writer, 2, DropNewest)
+
+    _, err := asyncWriter.Write([]byte("1\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    <-writer.started
+
+    for _, line := range []string{"2\n", "3\n", "4\n", "5\n"} {
+        _, err = asyncWriter.Write([]byte(line))
+        g.Expect(err).ToNot(gm.HaveOccurred())
+    }
+    g.Expect(asyncWriter.Dropped()).To(gm.Equal(uint64(2)))
+
+    close(writer.release)
+    err = asyncWriter.Close()
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    g.Expect(writer.String()).To(gm.Equal("1\n2\n3\n"))
+}
+
+func TestAsyncWriterDropOldest(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    writer := newBlockingWriter()
+    asyncWriter := NewAsyncWriter(writer, 2, DropOldest)
+
+    _, err := asyncWriter.Write([]byte("1\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    <-writer.started
+
+    for _, line := range []string{"2\n", "3\n", "4\n", "5\n"} {
+        _, err = asyncWriter.Write([]byte(line))
+        g.Expect(err).ToNot(gm.HaveOccurred())
+    }
+    g.Expect(asyncWriter.Dropped()).To(gm.Equal(uint64(2)))
+
+    close(writer.release)
+    err = asyncWriter.Close()
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    g.Expect(writer.String()).To(gm.Equal("1\n4\n5\n"))
+}
+
+func TestAsyncWriterClose(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    builder := new(lockedBuilder)
+    asyncWriter := NewAsyncWriter(builder, 0, Block)
+
+    buf := []byte("Foo\n")
+    _, err := asyncWriter.Write(buf)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    // NOTE: The AsyncWriter must have its own copy of what was written.
+    copy(buf, "Bar\n")
+
+    err = asyncWriter.Close()
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(builder.String()).To(gm.Equal("Foo\n"))
+
+    _, err = asyncWriter.Write([]byte("Baz\n"))
+    g.Expect(err).To(gm.Equal(ErrAsyncWriterClosed))
+    g.Expect(asyncWriter.Flush()).To(gm.Succeed())
+    g.Expect(asyncWriter.Close()).To(gm.Succeed())
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+    "sync"
+    "time"
+)
+
+// Clock provides the current time for the logs made by a Logger.
+type Clock interface {
+    Now() time.Time
+}
+
+// SystemClock is the default Clock which uses the system time.
+var SystemClock Clock = systemClock{}
+
+var (
+    globalClockRWMutex sync.RWMutex
+    globalClock = SystemClock
+)
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+    return time.Now()
+}
+
+// FixedClock is a Clock which always reports the same time until it is
+// changed. It's intended for making log output deterministic in tests.
+type FixedClock struct {
+    mutex sync.RWMutex
+    now time.Time
+}
+
+// NewFixedClock creates a new FixedClock set to the provided time.
+func NewFixedClock(now time.Time) *FixedClock {
+    return &FixedClock{
+        now: now,
+    }
+}
+
+// Now returns the time this clock is set to.
+func (self *FixedClock) Now() time.Time {
+    self.mutex.RLock()
+    defer self.mutex.RUnlock()
+    return self.now
+}
+
+// Set changes the time this clock is set to.
+func (self *FixedClock) Set(now time.Time) {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+    self.now = now
+}
+
+// Advance moves this clock forward by the provided duration.
+func (self *FixedClock) Advance(duration time.Duration) {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+    self.now = self.now.Add(duration)
+}
+
+// GetGlobalClock returns the Clock used by all Loggers which don't have their
+// own Clock set.
+func GetGlobalClock() Clock {
+    globalClockRWMutex.RLock()
+    defer globalClockRWMutex.RUnlock()
+    return globalClock
+}
+
+// SetGlobalClock sets the Clock used by all Loggers which don't have their
+// own Clock set. Passing nil restores the SystemClock.
+func SetGlobalClock(clock Clock) {
+    if clock == nil {
+        clock = SystemClock
+    }
+
+    globalClockRWMutex.Lock()
+    defer globalClockRWMutex.Unlock()
+    globalClock = clock
+}
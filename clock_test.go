@@ -0,0 +1,86 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "math/rand"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestFixedClock(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    testTime := time.Date(2019, 3, 9, 14, 59, 50, 0, time.UTC)
+    clock := NewFixedClock(testTime)
+    g.Expect(clock.Now()).To(gm.Equal(testTime))
+
+    clock.Advance(time.Second)
+    g.Expect(clock.Now()).To(gm.Equal(testTime.Add(time.Second)))
+
+    clock.Set(testTime)
+    g.Expect(clock.Now()).To(gm.Equal(testTime))
+}
+
+func TestLoggerClockGolden(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    clock := NewFixedClock(
+        time.Date(2019, 3, 9, 14, 59, 50, 123000000, time.UTC),
+    )
+
+    expected := map[LogFormat]string{
+        JSON: `{"timestamp":1552143590,"log_level":"INFO",` +
+            `"message":"Foo","bar":"baz qux"}` + "\n",
+        Standard: `2019-03-09T14:59:50 INFO bar="baz qux" Foo` + "\n",
+        StandardExtended: "timestamp           | log_level | message | bar    \n" +
+            "2019-03-09T14:59:50 | INFO      | Foo     | baz qux\n",
+        Logfmt: `ts=2019-03-09T14:59:50.123Z level=info msg=Foo ` +
+            `bar="baz qux"` + "\n",
+        Console: `2019-03-09 14:59:50.123 INFO  Foo bar="baz qux"` + "\n",
+    }
+
+    for format, line := range expected {
+        var builder strings.Builder
+        newLogger, err := NewLogger(
+            "test" + strconv.Itoa(rand.Int()),
+            WithLogWriters(&builder),
+            WithFormat(format),
+            WithClock(clock),
+            WithUTC(true),
+        )
+        g.Expect(err).ToNot(gm.HaveOccurred())
+
+        newLogger.Info("Foo", Extra("bar", "baz qux"))
+        newLogger.Close()
+
+        g.Expect(builder.String()).To(gm.Equal(line))
+    }
+}
+
+func TestGlobalClock(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    clock := NewFixedClock(time.Unix(1552143590, 0))
+    SetGlobalClock(clock)
+    defer SetGlobalClock(nil)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo")
+
+    g.Expect(builder.String()).To(gm.Equal(
+        `{"timestamp":1552143590,"log_level":"INFO","message":"Foo"}` + "\n",
+    ))
+    g.Expect(GetGlobalClock()).To(gm.BeIdenticalTo(clock))
+}
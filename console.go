@@ -0,0 +1,166 @@
+package logging
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "strings"
+)
+
+// ANSI escape codes used by the Console format.
+const (
+    ansiReset = "\x1b[0m"
+    ansiDim = "\x1b[2m"
+    ansiBoldRed = "\x1b[1;31m"
+    ansiRed = "\x1b[31m"
+    ansiGreen = "\x1b[32m"
+    ansiYellow = "\x1b[33m"
+    ansiBlue = "\x1b[34m"
+    ansiCyan = "\x1b[36m"
+)
+
+// NoColorEnvVar is the environment variable which, when set to any non-empty
+// value, disables color for the Console format. See https://no-color.org.
+const NoColorEnvVar = "NO_COLOR"
+
+const (
+    consoleTimestampFormat TimestampFormat = "2006-01-02 15:04:05.000"
+    consoleLevelWidth = 5
+    consoleIndent = "    "
+)
+
+var levelColors = map[LogLevel]string{
+    PANIC: ansiBoldRed,
+    FATAL: ansiBoldRed,
+    ERROR: ansiRed,
+    WARN: ansiYellow,
+    INFO: ansiGreen,
+    DEBUG: ansiBlue,
+    TRACE: ansiDim,
+}
+
+// ConsoleFormatter is the built-in Formatter backing the Console LogFormat.
+// It colors its output only when writing to a terminal and the NO_COLOR
+// environment variable isn't set.
+var ConsoleFormatter Formatter = consoleFormatter{autoColor: true}
+
+// NewConsoleFormatter creates a Console Formatter which always (or never)
+// uses color regardless of the writer it's writing to.
+func NewConsoleFormatter(color bool) Formatter {
+    return consoleFormatter{color: color}
+}
+
+type consoleFormatter struct {
+    color bool
+    autoColor bool
+}
+
+// ForWriter decides whether color should be used for the provided writer if
+// this formatter is auto-detecting color.
+func (self consoleFormatter) ForWriter(w io.Writer) Formatter {
+    if !self.autoColor {
+        return self
+    }
+
+    return consoleFormatter{color: colorEnabledFor(w)}
+}
+
+func colorEnabledFor(w io.Writer) bool {
+    if os.Getenv(NoColorEnvVar) != "" {
+        return false
+    }
+
+    return isTerminal(w)
+}
+
+func isTerminal(w io.Writer) bool {
+    file, ok := w.(*os.File)
+    if !ok {
+        return false
+    }
+
+    info, err := file.Stat()
+    if err != nil {
+        return false
+    }
+
+    return info.Mode() & os.ModeCharDevice != 0
+}
+
+func (self consoleFormatter) paint(color, text string) string {
+    if !self.color || color == "" {
+        return text
+    }
+
+    return color + text + ansiReset
+}
+
+// Format outputs the record in a human friendly format:
+//   2019-03-09 14:59:50.123 INFO  Hello world! key=value
+// Any multi-line field values (such as the stack from Logger.Exception) are
+// printed indented on the lines following the log line.
+func (self consoleFormatter) Format(record *Record) ([]byte, error) {
+    buf := new(bytes.Buffer)
+
+    var parts []string
+
+    names := record.FieldNames
+    if names.Timestamp != OmitField {
+        formattedTime := recordTimestamp(record).withDefault(
+            consoleTimestampFormat,
+        ).String()
+        parts = append(parts, self.paint(ansiDim, formattedTime))
+    }
+    if names.Level != OmitField {
+        level := string(record.Level)
+        if len(level) < consoleLevelWidth {
+            level = padStringRight(level, " ", consoleLevelWidth - len(level))
+        }
+        parts = append(parts, self.paint(levelColors[record.Level], level))
+    }
+    if names.Message != OmitField {
+        parts = append(parts, record.Message)
+    }
+    buf.WriteString(strings.Join(parts, " "))
+
+    callerFields := names.merge(defaultFieldNames).callerFields(record)
+
+    var multiLineFields []Field
+    for _, field := range append(callerFields, record.Fields...) {
+        value := logfmtValueString(field.Value)
+        if strings.Contains(strings.TrimRight(value, "\n"), "\n") {
+            multiLineFields = append(
+                multiLineFields, Field{Key: field.Key, Value: value},
+            )
+            continue
+        }
+        if logfmtNeedsQuoting(value) {
+            value = strconv.Quote(value)
+        }
+
+        fmt.Fprintf(
+            buf, " %s=%s", self.paint(ansiCyan, logfmtKey(field.Key)), value,
+        )
+    }
+
+    for _, field := range multiLineFields {
+        fmt.Fprintf(
+            buf, "\n%s%s:", consoleIndent, self.paint(ansiCyan, field.Key),
+        )
+        lines := strings.Split(
+            strings.TrimRight(field.Value.(string), "\n"), "\n",
+        )
+        color := ""
+        if field.Key == "error" {
+            color = ansiRed
+        }
+        for _, line := range lines {
+            buf.WriteString("\n" + consoleIndent + consoleIndent)
+            buf.WriteString(self.paint(color, line))
+        }
+    }
+
+    return buf.Bytes(), nil
+}
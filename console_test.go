@@ -0,0 +1,154 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "errors"
+    "io"
+    "math/rand"
+    "os"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+
+    gm "github.com/onsi/gomega"
+)
+
+func consoleTestRecord() *Record {
+    return &Record{
+        Time: time.Date(2019, 3, 9, 14, 59, 50, 123000000, time.UTC),
+        Level: WARN,
+        Message: "Hello world!",
+        Fields: []Field{
+            {Key: "foo", Value: "bar baz"},
+            {Key: "error", Value: "test err!\nmain.foo\n\tfoo.go:12"},
+        },
+    }
+}
+
+func TestConsoleFormatterNoColor(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    result, err := NewConsoleFormatter(false).Format(consoleTestRecord())
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(string(result)).To(gm.Equal(
+        "2019-03-09 14:59:50.123 WARN  Hello world! foo=\"bar baz\"\n" +
+            "    error:\n" +
+            "        test err!\n" +
+            "        main.foo\n" +
+            "        \tfoo.go:12",
+    ))
+}
+
+func TestConsoleFormatterColor(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    result, err := NewConsoleFormatter(true).Format(consoleTestRecord())
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(string(result)).To(gm.HavePrefix(
+        ansiDim + "2019-03-09 14:59:50.123" + ansiReset + " " +
+            ansiYellow + "WARN " + ansiReset + " Hello world! " +
+            ansiCyan + "foo" + ansiReset + "=\"bar baz\"",
+    ))
+    g.Expect(string(result)).To(gm.ContainSubstring(
+        ansiRed + "test err!" + ansiReset,
+    ))
+}
+
+func TestConsoleFormatterForWriter(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    writerFormatter := ConsoleFormatter.(WriterFormatter)
+
+    g.Expect(writerFormatter.ForWriter(new(strings.Builder))).To(
+        gm.Equal(NewConsoleFormatter(false)),
+    )
+    g.Expect(NewConsoleFormatter(true).(WriterFormatter).ForWriter(
+        new(strings.Builder),
+    )).To(gm.Equal(NewConsoleFormatter(true)))
+
+    previous, hadPrevious := os.LookupEnv(NoColorEnvVar)
+    os.Setenv(NoColorEnvVar, "1")
+    defer func() {
+        if hadPrevious {
+            os.Setenv(NoColorEnvVar, previous)
+        } else {
+            os.Unsetenv(NoColorEnvVar)
+        }
+    }()
+
+    g.Expect(writerFormatter.ForWriter(os.Stdout)).To(
+        gm.Equal(NewConsoleFormatter(false)),
+    )
+}
+
+// countingWriterFormatter counts the number of times ForWriter is called.
+type countingWriterFormatter struct {
+    Formatter
+    resolved *int
+}
+
+func (self countingWriterFormatter) ForWriter(w io.Writer) Formatter {
+    *self.resolved++
+    return self.Formatter
+}
+
+func TestLoggerWriterFormatterResolvedOnce(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var resolved int
+    formatter := countingWriterFormatter{
+        Formatter: NewConsoleFormatter(false),
+        resolved: &resolved,
+    }
+    var builder, sinkBuilder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormatter(formatter),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    for i := 0; i < 3; i++ {
+        newLogger.Info("Foo")
+    }
+    g.Expect(resolved).To(gm.Equal(1))
+    g.Expect(strings.Count(builder.String(), "Foo")).To(gm.Equal(3))
+
+    err = newLogger.AddSink(&sinkBuilder, UnsetLogLevel, JSON)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    newLogger.Info("Bar")
+    g.Expect(resolved).To(gm.Equal(2))
+    g.Expect(sinkBuilder.String()).To(gm.ContainSubstring(`"message":"Bar"`))
+
+    // NOTE: Changing the formatter drops the resolved formatters.
+    newLogger.SetFormat(JSON)
+    newLogger.Info("Baz")
+    g.Expect(resolved).To(gm.Equal(2))
+    g.Expect(builder.String()).To(gm.ContainSubstring(`"message":"Baz"`))
+}
+
+func TestLoggerConsole(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    g.Expect(FormatFromString("console")).To(gm.Equal(Console))
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(Console),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Exception(errors.New("test err!"), "Foo", Extra("bar", 1))
+
+    logResult := builder.String()
+    t.Log("\n" + logResult)
+    g.Expect(logResult).ToNot(gm.ContainSubstring("\x1b["))
+    g.Expect(logResult).To(gm.MatchRegexp(
+        `^[^\s]+ [^\s]+ ERROR Foo bar=1\n    error:\n        test err!\n`,
+    ))
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+    "context"
+
+    "github.com/pkg/errors"
+)
+
+// ContextExtractor is a function which produces an Extras map from a
+// context.Context when called. Like an ExtrasGenerator it is evaluated at
+// log-time but only for logs made with one of the Context methods.
+type ContextExtractor func(ctx context.Context) (Extras, error)
+
+// ContextValue makes a ContextExtractor which logs the value stored in the
+// context under contextKey as key. Nothing is logged if the context has no
+// value for contextKey.
+func ContextValue(key string, contextKey interface{}) ContextExtractor {
+    return func(ctx context.Context) (Extras, error) {
+        value := ctx.Value(contextKey)
+        if value == nil {
+            return nil, nil
+        }
+
+        return Extra(key, value), nil
+    }
+}
+
+type loggerContextKey struct{}
+
+// NewContext returns a copy of ctx which carries the provided logger.
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+    return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext gets the Logger carried by ctx or the root logger if it
+// doesn't carry one.
+func FromContext(ctx context.Context) *Logger {
+    if ctx != nil {
+        logger, ok := ctx.Value(loggerContextKey{}).(*Logger)
+        if ok && logger != nil {
+            return logger
+        }
+    }
+
+    return GetRootLogger()
+}
+
+// runContextExtractors runs each of the extractors with ctx.
+func runContextExtractors(
+    ctx context.Context, extractors []ContextExtractor,
+) ([]Extras, error) {
+    var allExtras []Extras
+    for i, extractor := range extractors {
+        newExtras, err := extractor(ctx)
+        if err != nil {
+            return nil, errors.Wrapf(
+                err, "Error while running context extractor #%d", i,
+            )
+        }
+        allExtras = append(allExtras, newExtras)
+    }
+
+    return allExtras, nil
+}
@@ -0,0 +1,163 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "context"
+    "errors"
+    "math/rand"
+    "os"
+    "strconv"
+    "strings"
+    "testing"
+
+    gm "github.com/onsi/gomega"
+)
+
+type testContextKey string
+
+func TestFromContext(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    newLogger, err := NewLogger("test" + strconv.Itoa(rand.Int()))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    ctx := NewContext(context.Background(), newLogger)
+
+    g.Expect(FromContext(ctx)).To(gm.BeIdenticalTo(newLogger))
+    g.Expect(FromContext(context.Background())).To(
+        gm.BeIdenticalTo(GetRootLogger()),
+    )
+}
+
+func TestLoggerContextExtractors(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithContextExtractors(
+            ContextValue("request_id", testContextKey("request_id")),
+        ),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.AddContextExtractors(
+        ContextValue("tenant", testContextKey("tenant")),
+    )
+
+    ctx := context.WithValue(
+        context.Background(), testContextKey("request_id"), "abc",
+    )
+    newLogger.InfoContext(ctx, "Foo", Extras{"bar": 1})
+    newLogger.Info("Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"bar":1,"request_id":"abc"}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}\n`,
+    ))
+}
+
+func TestLoggerExceptionContext(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithContextExtractors(
+            ContextValue("request_id", testContextKey("request_id")),
+        ),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    ctx := context.WithValue(
+        context.Background(), testContextKey("request_id"), "abc",
+    )
+    newLogger.ExceptionContext(
+        ctx, errors.New("test err!"), "Foo", Extras{"bar": 1},
+    )
+    ExceptionContext(
+        NewContext(ctx, newLogger), errors.New("test err!"), "Foo",
+    )
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"ERROR","message":"Foo","bar":1,` +
+            `"error":{"message":"test err!",[^\n]+},"request_id":"abc"}\n` +
+            `{"timestamp":\d+,"log_level":"ERROR","message":"Foo",` +
+            `"error":{"message":"test err!",[^\n]+},"request_id":"abc"}\n$`,
+    ))
+}
+
+func TestLoggerContextExtractorFailure(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithContextExtractors(func(context.Context) (Extras, error) {
+            return nil, errors.New("Bad extractor")
+        }),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.WarnContext(context.Background(), "Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"ERROR",` +
+            `"message":"Error while running logger context extractors.",` +
+            `"error":{"message":"Error while running context extractor ` +
+            `#0: Bad extractor","type":"\*errors.withStack",` +
+            `"causes":\[{"message":"Bad extractor",`,
+    ))
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"WARN","message":"Foo"}`,
+    ))
+}
+
+func TestGlobalContext(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    rootLogger := GetRootLogger()
+    rootLogger.SetWriters(&builder)
+    AddGlobalContextExtractors(
+        ContextValue("trace_id", testContextKey("trace_id")),
+    )
+    defer func() {
+        rootLogger.SetWriters(os.Stdout)
+        SetGlobalContextExtractors()
+    }()
+
+    var childBuilder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&childBuilder),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    ctx := context.WithValue(
+        context.Background(), testContextKey("trace_id"), "xyz",
+    )
+    ErrorContext(ctx, "Foo")
+    ErrorContext(NewContext(ctx, newLogger.With(Extras{"bar": 1})), "Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"ERROR","message":"Foo",` +
+            `"trace_id":"xyz"}\n$`,
+    ))
+    g.Expect(childBuilder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"ERROR","message":"Foo",` +
+            `"bar":1,"trace_id":"xyz"}\n$`,
+    ))
+}
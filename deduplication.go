@@ -0,0 +1,195 @@
+package logging
+
+import (
+    "fmt"
+    "reflect"
+    "sync"
+    "time"
+
+    "github.com/pkg/errors"
+)
+
+// Keys & message used for the logs reporting how many times a log was
+// repeated.
+const (
+    repeatedReportMessage = "Message repeated %d times"
+    repeatedMessageKey = "repeated_message"
+    repeatedCountKey = "repeated_count"
+)
+
+// WithDeduplication collapses identical consecutive logs made by the new
+// Logger; only the first is logged and the rest are counted. Logs are
+// identical if they're made at the same level with the same message & extras
+// by the same Logger (or child made with With).
+//
+// The count is reported as a single "Message repeated N times" log once a
+// different log is made, when the Logger is flushed or closed or after
+// timeout if the repeats carry on. FATAL and PANIC logs are never collapsed.
+func WithDeduplication(timeout time.Duration) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        if timeout <= 0 {
+            return errors.Errorf(
+                "Deduplication timeout must be positive: %s", timeout,
+            )
+        }
+        loggerConfig.deduplicationTimeout = &timeout
+
+        return nil
+    }
+}
+
+// dedupRecord is what identifies a log for deduplication.
+type dedupRecord struct {
+    logger *Logger
+    level LogLevel
+    message string
+    extras []Extras
+}
+
+func (self *dedupRecord) matches(other *dedupRecord) bool {
+    // NOTE: Children made with With share the same state so the bound fields
+    //       they're made with have to match too.
+    return self.logger.fields == other.logger.fields &&
+        self.level == other.level &&
+        self.message == other.message &&
+        reflect.DeepEqual(self.extras, other.extras)
+}
+
+// snapshot copies the record with its own copy of the extras so that callers
+// reusing (and changing) the same Extras between logs don't change the record
+// the next log is compared against.
+func (self *dedupRecord) snapshot() *dedupRecord {
+    var extras []Extras
+    if self.extras != nil {
+        extras = make([]Extras, len(self.extras))
+    }
+    for i, extra := range self.extras {
+        if extra == nil {
+            continue
+        }
+        extras[i] = make(Extras, len(extra))
+        for key, value := range extra {
+            extras[i][key] = value
+        }
+    }
+
+    return &dedupRecord{
+        logger: self.logger,
+        level: self.level,
+        message: self.message,
+        extras: extras,
+    }
+}
+
+// dedupReport is the number of times a log was repeated.
+type dedupReport struct {
+    record *dedupRecord
+    repeats int
+}
+
+// deduplicator counts the repeats of the last log made.
+type deduplicator struct {
+    timeout time.Duration
+
+    mutex sync.Mutex
+    last *dedupRecord
+    repeats int
+    // timer reports the repeats once the timeout is over; it's only set
+    // while there are repeats.
+    timer *time.Timer
+    // timerGeneration identifies the current timer so one which has been
+    // stopped can't report repeats which aren't its own.
+    timerGeneration int
+}
+
+func newDeduplicator(timeout time.Duration) *deduplicator {
+    return &deduplicator{
+        timeout: timeout,
+    }
+}
+
+// check decides whether the log should be logged (because it isn't a repeat
+// of the last one) and gets the report for the previous log's repeats if it
+// has ended a run of them.
+func (self *deduplicator) check(record *dedupRecord) (bool, *dedupReport) {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+
+    if self.last != nil && self.last.matches(record) {
+        self.repeats++
+        if self.timer == nil {
+            self.timerGeneration++
+            generation := self.timerGeneration
+            self.timer = time.AfterFunc(self.timeout, func() {
+                self.expire(generation)
+            })
+        }
+
+        return false, nil
+    }
+
+    report := self.report()
+    self.last = record.snapshot()
+
+    return true, report
+}
+
+// expire writes the report for the repeats when the timer goes off unless
+// they've already been reported.
+func (self *deduplicator) expire(generation int) {
+    self.mutex.Lock()
+    if self.timer == nil || self.timerGeneration != generation {
+        self.mutex.Unlock()
+        return
+    }
+    report := self.report()
+    self.mutex.Unlock()
+
+    if report != nil {
+        logger := report.record.logger
+        logger.writeDedupReport(logger.loadState(), report)
+    }
+}
+
+// drain gets the report for the repeats of the last log if there have been
+// any since they were last reported.
+func (self *deduplicator) drain() *dedupReport {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+
+    return self.report()
+}
+
+func (self *deduplicator) report() *dedupReport {
+    if self.timer != nil {
+        self.timer.Stop()
+        self.timer = nil
+    }
+    if self.repeats == 0 {
+        return nil
+    }
+    report := &dedupReport{
+        record: self.last,
+        repeats: self.repeats,
+    }
+    self.repeats = 0
+
+    return report
+}
+
+func (self *Logger) writeDedupReport(state *loggerState, report *dedupReport) {
+    if report == nil {
+        return
+    }
+
+    fields := []Field{
+        {Key: repeatedMessageKey, Value: report.record.message},
+        {Key: repeatedCountKey, Value: report.repeats},
+    }
+    self.writeRecord(nil, state, self.newRecord(
+        state,
+        report.record.level,
+        fmt.Sprintf(repeatedReportMessage, report.repeats),
+        fields,
+    ))
+}
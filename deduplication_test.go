@@ -0,0 +1,128 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "math/rand"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestLoggerDeduplication(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithDeduplication(time.Hour),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    child := newLogger.With(Extras{"a": 1})
+    for i := 0; i < 3; i++ {
+        newLogger.Info("Foo", Extras{"b": 2})
+    }
+    newLogger.Info("Foo", Extras{"b": 3})
+    child.Info("Foo", Extras{"b": 3})
+    child.Info("Foo", Extras{"b": 3})
+    child.Warn("Foo", Extras{"b": 3})
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"INFO","message":"Foo","b":2}\n` +
+            `{"timestamp":\d+,"log_level":"INFO",` +
+            `"message":"Message repeated 2 times",` +
+            `"repeated_message":"Foo","repeated_count":2}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Foo","b":3}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"a":1,"b":3}\n` +
+            `{"timestamp":\d+,"log_level":"INFO",` +
+            `"message":"Message repeated 1 times",` +
+            `"repeated_message":"Foo","repeated_count":1}\n` +
+            `{"timestamp":\d+,"log_level":"WARN","message":"Foo",` +
+            `"a":1,"b":3}\n$`,
+    ))
+
+    builder.Reset()
+    child.Warn("Foo", Extras{"b": 3})
+    g.Expect(builder.String()).To(gm.BeEmpty())
+    g.Expect(newLogger.Flush()).To(gm.Succeed())
+    g.Expect(newLogger.Flush()).To(gm.Succeed())
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"WARN",` +
+            `"message":"Message repeated 1 times",` +
+            `"repeated_message":"Foo","repeated_count":1}\n$`,
+    ))
+}
+
+func TestLoggerDeduplicationReusedExtras(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithDeduplication(time.Hour),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    extras := Extras{}
+    for i := 0; i < 3; i++ {
+        extras["attempt"] = i
+        newLogger.Info("Foo", extras)
+    }
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"INFO","message":"Foo","attempt":0}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Foo","attempt":1}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Foo","attempt":2}\n$`,
+    ))
+}
+
+func TestLoggerDeduplicationTimeout(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder lockedBuilder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithDeduplication(10 * time.Millisecond),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    for i := 0; i < 3; i++ {
+        newLogger.Info("Foo")
+    }
+
+    g.Eventually(builder.String).Should(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"INFO","message":"Foo"}\n` +
+            `{"timestamp":\d+,"log_level":"INFO",` +
+            `"message":"Message repeated 2 times",` +
+            `"repeated_message":"Foo","repeated_count":2}\n$`,
+    ))
+
+    // NOTE: Repeats carry on being collapsed after the timeout.
+    newLogger.Info("Foo")
+    g.Eventually(builder.String).Should(gm.HaveSuffix(
+        `"message":"Message repeated 1 times",` +
+            `"repeated_message":"Foo","repeated_count":1}` + "\n",
+    ))
+}
+
+func TestWithDeduplicationErrors(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    _, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()), WithDeduplication(0),
+    )
+    g.Expect(err).To(gm.HaveOccurred())
+}
@@ -0,0 +1,205 @@
+package logging
+
+import (
+    "encoding/json"
+    "fmt"
+    "runtime"
+    "strings"
+
+    "github.com/pkg/errors"
+)
+
+// maxStackDepth is the most frames captured for an error which doesn't carry
+// its own stack.
+const maxStackDepth = 32
+
+type (
+    // errorField is the value logged for the error passed to Exception. It
+    // marshals to a structured object for JSON and to the error's "%+v" text
+    // otherwise.
+    errorField struct {
+        err error
+        // stack is captured where the error was logged and is only set if
+        // the error doesn't carry a stack of its own.
+        stack []Caller
+    }
+
+    // errorDetails is the structured form of an error.
+    errorDetails struct {
+        Message string `json:"message"`
+        Type string `json:"type"`
+        Causes []errorDetails `json:"causes,omitempty"`
+        Stack []Caller `json:"stack,omitempty"`
+    }
+
+    stackTracer interface {
+        StackTrace() errors.StackTrace
+    }
+
+    causer interface {
+        Cause() error
+    }
+)
+
+// newErrorField makes an errorField for err capturing the stack from skip
+// frames above the function calling newErrorField if err has no stack. No
+// stack is captured for a nil err.
+func newErrorField(err error, skip int) *errorField {
+    field := &errorField{err: err}
+    if err != nil && originalStack(err) == nil {
+        field.stack = callersStack(skip + 1)
+    }
+
+    return field
+}
+
+// unwrapError gets the error(s) directly wrapped by err supporting Go 1.13
+// style Unwrap (including errors.Join) as well as pkg/errors' Cause.
+func unwrapError(err error) []error {
+    switch wrapper := err.(type) {
+    case interface{ Unwrap() []error }:
+        return wrapper.Unwrap()
+    case interface{ Unwrap() error }:
+        if cause := wrapper.Unwrap(); cause != nil {
+            return []error{cause}
+        }
+    case causer:
+        if cause := wrapper.Cause(); cause != nil {
+            return []error{cause}
+        }
+    }
+
+    return nil
+}
+
+// originalStack gets the stack of the deepest error in err's chain which has
+// one, or nil if none of them do.
+func originalStack(err error) []Caller {
+    var stack []Caller
+    for err != nil {
+        if tracer, ok := err.(stackTracer); ok {
+            stack = framesFromStackTrace(tracer.StackTrace())
+        }
+
+        causes := unwrapError(err)
+        if len(causes) != 1 {
+            break
+        }
+        err = causes[0]
+    }
+
+    return stack
+}
+
+func framesFromStackTrace(stackTrace errors.StackTrace) []Caller {
+    frames := make([]Caller, 0, len(stackTrace))
+    for _, frame := range stackTrace {
+        // NOTE: Frames hold the return address so step back to the call.
+        pc := uintptr(frame) - 1
+        fn := runtime.FuncForPC(pc)
+        if fn == nil {
+            continue
+        }
+        file, line := fn.FileLine(pc)
+        frames = append(frames, Caller{
+            File: file,
+            Line: line,
+            Function: fn.Name(),
+        })
+    }
+
+    return frames
+}
+
+// callersStack captures the stack from skip frames above the function
+// calling callersStack.
+func callersStack(skip int) []Caller {
+    var pcs [maxStackDepth]uintptr
+    count := runtime.Callers(skip + 2, pcs[:])
+
+    var stack []Caller
+    frames := runtime.CallersFrames(pcs[:count])
+    for {
+        frame, more := frames.Next()
+        stack = append(stack, Caller{
+            File: frame.File,
+            Line: frame.Line,
+            Function: frame.Function,
+        })
+        if !more {
+            break
+        }
+    }
+
+    return stack
+}
+
+func newErrorDetails(err error) errorDetails {
+    return errorDetails{
+        Message: err.Error(),
+        Type: fmt.Sprintf("%T", err),
+        Causes: errorCauses(err),
+    }
+}
+
+// errorCauses gets the chain of errors wrapped by err. Wrappers which don't
+// change the message (like pkg/errors' WithStack) are skipped and each error
+// joined by errors.Join gets its own chain of causes.
+func errorCauses(err error) []errorDetails {
+    var causes []errorDetails
+    message := err.Error()
+    for {
+        wrapped := unwrapError(err)
+        if len(wrapped) == 0 {
+            return causes
+        }
+        if len(wrapped) > 1 {
+            for _, joined := range wrapped {
+                if joined != nil {
+                    causes = append(causes, newErrorDetails(joined))
+                }
+            }
+            return causes
+        }
+
+        err = wrapped[0]
+        if err.Error() != message {
+            message = err.Error()
+            causes = append(causes, errorDetails{
+                Message: message,
+                Type: fmt.Sprintf("%T", err),
+            })
+        }
+    }
+}
+
+// MarshalJSON outputs the error as an object with its message, type, causes
+// and the stack it originated from. A nil error is output as null.
+func (self *errorField) MarshalJSON() ([]byte, error) {
+    if self.err == nil {
+        return []byte("null"), nil
+    }
+
+    details := newErrorDetails(self.err)
+    details.Stack = originalStack(self.err)
+    if details.Stack == nil {
+        details.Stack = self.stack
+    }
+
+    return json.Marshal(details)
+}
+
+// String outputs the error the same way as "%+v" which includes the stack for
+// pkg/errors errors. The captured stack is appended in the same style for
+// errors without one.
+func (self *errorField) String() string {
+    var builder strings.Builder
+    fmt.Fprintf(&builder, "%+v", self.err)
+    for _, frame := range self.stack {
+        fmt.Fprintf(
+            &builder, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line,
+        )
+    }
+
+    return builder.String()
+}
@@ -0,0 +1,131 @@
+/* #nosec G404 */
+package logging
+
+import (
+    stderrors "errors"
+    "fmt"
+    "math/rand"
+    "runtime"
+    "strconv"
+    "strings"
+    "testing"
+
+    gm "github.com/onsi/gomega"
+    "github.com/pkg/errors"
+)
+
+func TestErrorFieldCauses(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    root := stderrors.New("root")
+    wrapped := fmt.Errorf("middle: %w", errors.Wrap(root, "inner"))
+
+    details := newErrorDetails(wrapped)
+    g.Expect(details.Message).To(gm.Equal("middle: inner: root"))
+    g.Expect(details.Type).To(gm.Equal("*fmt.wrapError"))
+    g.Expect(details.Causes).To(gm.Equal([]errorDetails{
+        {Message: "inner: root", Type: "*errors.withStack"},
+        {Message: "root", Type: "*errors.errorString"},
+    }))
+}
+
+func TestErrorFieldJoinedCauses(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    joined := stderrors.Join(
+        stderrors.New("first"),
+        fmt.Errorf("second: %w", stderrors.New("cause")),
+    )
+
+    details := newErrorDetails(joined)
+    g.Expect(details.Causes).To(gm.Equal([]errorDetails{
+        {Message: "first", Type: "*errors.errorString"},
+        {
+            Message: "second: cause",
+            Type: "*fmt.wrapError",
+            Causes: []errorDetails{
+                {Message: "cause", Type: "*errors.errorString"},
+            },
+        },
+    }))
+}
+
+func TestErrorFieldOriginalStack(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    _, _, line, _ := runtime.Caller(0)
+    err := errors.Wrap(errors.New("root"), "wrapped")
+
+    field := newErrorField(err, 0)
+    g.Expect(field.stack).To(gm.BeNil())
+
+    stack := originalStack(err)
+    g.Expect(stack).ToNot(gm.BeEmpty())
+    g.Expect(stack[0].Line).To(gm.Equal(line + 1))
+    g.Expect(stack[0].Function).To(gm.Equal(
+        "github.com/daihasso/slogging.TestErrorFieldOriginalStack",
+    ))
+    g.Expect(field.String()).To(gm.Equal(fmt.Sprintf("%+v", err)))
+}
+
+func TestErrorFieldCapturedStack(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    _, file, line, _ := runtime.Caller(0)
+    field := newErrorField(stderrors.New("root"), 0)
+
+    g.Expect(field.stack).ToNot(gm.BeEmpty())
+    g.Expect(field.stack[0]).To(gm.Equal(Caller{
+        File: file,
+        Line: line + 1,
+        Function: "github.com/daihasso/slogging.TestErrorFieldCapturedStack",
+    }))
+    g.Expect(field.String()).To(gm.HavePrefix(fmt.Sprintf(
+        "root\ngithub.com/daihasso/slogging.TestErrorFieldCapturedStack\n" +
+            "\t%s:%d",
+        file,
+        line + 1,
+    )))
+}
+
+func TestLoggerExceptionStandard(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(Standard),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Exception(errors.New("test err!"), "Oh noes, an error.")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        ` ERROR error="test err!\n` +
+            `github\.com/daihasso/slogging\.TestLoggerExceptionStandard\n` +
+            `\t[^\n]+/error_field_test\.go:\d+\n`,
+    ))
+}
+
+func TestLoggerExceptionSkipsStackWhenNotLogged(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithLogLevel(FATAL),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    testErr := stderrors.New("test err!")
+    allocs := testing.AllocsPerRun(10, func() {
+        newLogger.Exception(testErr, "Foo")
+    })
+    g.Expect(allocs).To(gm.BeZero())
+    g.Expect(builder.String()).To(gm.BeEmpty())
+}
@@ -64,3 +64,42 @@ func FunctionalExtras(
         return extras, nil
     }
 }
+
+// badKey is the key used for any values from keysAndValues that aren't part
+// of a valid key, value pair.
+const badKey = "!BADKEY"
+
+// extrasFromKeysAndValues converts alternating keys & values into Extras.
+// Each pair gets its own Extras so the order they were provided in is
+// preserved. Any Extras found in place of a key are used as is.
+// Malformed pairs (a non-string key or a key without a value) are reported
+// under the "!BADKEY" key rather than being dropped.
+func extrasFromKeysAndValues(keysAndValues []interface{}) []Extras {
+    var (
+        allExtras []Extras
+        badValues []interface{}
+    )
+    for i := 0; i < len(keysAndValues); i++ {
+        switch key := keysAndValues[i].(type) {
+        case Extras:
+            allExtras = append(allExtras, key)
+        case string:
+            if i + 1 >= len(keysAndValues) {
+                badValues = append(badValues, key)
+                continue
+            }
+            allExtras = append(allExtras, Extra(key, keysAndValues[i + 1]))
+            i++
+        default:
+            badValues = append(badValues, key)
+        }
+    }
+
+    if len(badValues) == 1 {
+        allExtras = append(allExtras, Extra(badKey, badValues[0]))
+    } else if len(badValues) > 1 {
+        allExtras = append(allExtras, Extra(badKey, badValues))
+    }
+
+    return allExtras
+}
@@ -0,0 +1,103 @@
+package logging
+
+// OmitField can be used as a name in FieldNames to leave that part of the log
+// out entirely.
+const OmitField = "-"
+
+// FieldNames configures the keys used for the reserved parts of a log. Any
+// name left empty will use the default for the format being used and any
+// name set to OmitField will be left out of the log.
+type FieldNames struct {
+    Timestamp string
+    Level string
+    Message string
+    // Caller is only used if the Logger is capturing caller information.
+    Caller string
+}
+
+// Default FieldNames for the built-in formats.
+var (
+    defaultFieldNames = FieldNames{
+        Timestamp: timestampKey,
+        Level: logLevelKey,
+        Message: messageKey,
+        Caller: callerKey,
+    }
+    logfmtFieldNames = FieldNames{
+        Timestamp: logfmtTimestampKey,
+        Level: logfmtLevelKey,
+        Message: logfmtMessageKey,
+        Caller: callerKey,
+    }
+)
+
+func fieldNameOr(name, fallback string) string {
+    if name == "" {
+        return fallback
+    }
+
+    return name
+}
+
+// merge fills in any unset names from fallback.
+func (self FieldNames) merge(fallback FieldNames) FieldNames {
+    return FieldNames{
+        Timestamp: fieldNameOr(self.Timestamp, fallback.Timestamp),
+        Level: fieldNameOr(self.Level, fallback.Level),
+        Message: fieldNameOr(self.Message, fallback.Message),
+        Caller: fieldNameOr(self.Caller, fallback.Caller),
+    }
+}
+
+// reservedFields pairs the provided values with their names, leaving out any
+// that are omitted.
+func (self FieldNames) reservedFields(
+    timestamp, level, message interface{},
+) []Field {
+    fields := make([]Field, 0, 3)
+    for _, field := range []Field{
+        {Key: self.Timestamp, Value: timestamp},
+        {Key: self.Level, Value: level},
+        {Key: self.Message, Value: message},
+    } {
+        if field.Key != OmitField {
+            fields = append(fields, field)
+        }
+    }
+
+    return fields
+}
+
+// reservedKeys gets all the names which aren't omitted.
+func (self FieldNames) reservedKeys() []string {
+    keys := make([]string, 0, 3)
+    for _, key := range []string{self.Timestamp, self.Level, self.Message} {
+        if key != OmitField {
+            keys = append(keys, key)
+        }
+    }
+
+    return keys
+}
+
+// callerFields gets a field for the record's Caller if it has one and it isn't
+// omitted. The value is left as a *Caller which marshals to an object in JSON
+// and is output as "file:line function" otherwise.
+func (self FieldNames) callerFields(record *Record) []Field {
+    if record.Caller == nil || self.Caller == OmitField {
+        return nil
+    }
+
+    return []Field{{Key: self.Caller, Value: record.Caller}}
+}
+
+// recordReservedKeys gets the reserved keys for the record which includes the
+// caller's name if the record has a Caller.
+func (self FieldNames) recordReservedKeys(record *Record) []string {
+    reservedKeys := self.reservedKeys()
+    if record.Caller != nil && self.Caller != OmitField {
+        reservedKeys = append(reservedKeys, self.Caller)
+    }
+
+    return reservedKeys
+}
@@ -0,0 +1,97 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "math/rand"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestFieldNamesMerge(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    merged := FieldNames{Message: "msg"}.merge(defaultFieldNames)
+    g.Expect(merged).To(gm.Equal(FieldNames{
+        Timestamp: "timestamp",
+        Level: "log_level",
+        Message: "msg",
+        Caller: "caller",
+    }))
+
+    g.Expect(FieldNames{
+        Timestamp: OmitField, Level: "level", Message: "msg",
+    }.reservedKeys()).To(gm.Equal([]string{"level", "msg"}))
+}
+
+func TestLoggerFieldNames(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    clock := NewFixedClock(time.Unix(1552143590, 0))
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithClock(clock),
+        WithFieldNames(FieldNames{
+            Timestamp: "@timestamp",
+            Level: "log.level",
+            Message: "msg",
+        }),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo", Extras{"msg": "clash", "message": "fine"})
+
+    g.Expect(builder.String()).To(gm.Equal(
+        `{"@timestamp":1552143590,"log.level":"INFO","msg":"Foo",` +
+            `"message":"fine","fields.msg":"clash"}` + "\n",
+    ))
+
+    builder.Reset()
+    newLogger.SetFormat(Logfmt)
+    newLogger.SetFieldNames(FieldNames{Timestamp: OmitField})
+
+    newLogger.Info("Foo")
+
+    g.Expect(builder.String()).To(gm.Equal("level=info msg=Foo\n"))
+
+    builder.Reset()
+    newLogger.SetFormat(Standard)
+    newLogger.SetFieldNames(FieldNames{
+        Timestamp: "time", Level: OmitField,
+    })
+
+    newLogger.Info("Foo", Extra("bar", "baz"))
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(`^[^\s]+ bar="baz" Foo\n$`))
+}
+
+func TestGlobalFieldNames(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    SetGlobalFieldNames(FieldNames{Level: "level", Message: "msg"})
+    defer SetGlobalFieldNames(FieldNames{})
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithFieldNames(FieldNames{Message: "text"}),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"level":"INFO","text":"Foo"}\n$`,
+    ))
+}
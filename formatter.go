@@ -0,0 +1,198 @@
+package logging
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "regexp"
+    "strings"
+
+    "github.com/pkg/errors"
+)
+
+var keySantizationRegexp = regexp.MustCompile(`[\n\r\s]`)
+
+// Formatter turns a Record into the bytes that will be written for a single
+// log line.
+type Formatter interface {
+    Format(record *Record) ([]byte, error)
+}
+
+// WriterFormatter is an optional interface for a Formatter whose output
+// depends on the writer it is writing to. ForWriter is called for each writer
+// when a Logger's writers or formatter change and the returned Formatter is
+// used to format logs for that writer.
+type WriterFormatter interface {
+    Formatter
+    ForWriter(w io.Writer) Formatter
+}
+
+// FormatterFunc is an adapter to allow the use of an ordinary function as a
+// Formatter.
+type FormatterFunc func(record *Record) ([]byte, error)
+
+// Format calls the wrapped function.
+func (self FormatterFunc) Format(record *Record) ([]byte, error) {
+    return self(record)
+}
+
+// The built-in Formatters backing the JSON, Standard and StandardExtended
+// LogFormats.
+var (
+    JSONFormatter Formatter = jsonFormatter{}
+    StandardFormatter Formatter = standardFormatter{}
+    StandardExtendedFormatter Formatter = standardExtendedFormatter{}
+)
+
+// Used for standard formats so you don't get super weird logs. All bets are
+// off for JSON though.
+func sanitizeKey(key string) string {
+    return keySantizationRegexp.ReplaceAllString(strings.TrimSpace(key), "_")
+}
+
+func padStringRight(str, pad string, count int) string {
+    result := str
+    for i := 0; i < count; i++ {
+        result += pad
+    }
+
+    return result
+}
+
+func recordTimestamp(record *Record) timestamp {
+    return timestamp{record.Time, record.TimestampFormat}
+}
+
+type jsonFormatter struct{}
+
+// writeJSONPair writes a single "key":value pair to buf, preceded by a comma
+// if it isn't the first pair in the object.
+func writeJSONPair(
+    buf *bytes.Buffer, key string, value interface{},
+) error {
+    keyBytes, err := json.Marshal(key)
+    if err != nil {
+        return errors.Wrapf(err, "Error while marshalling key '%s'", key)
+    }
+    valueBytes, err := json.Marshal(value)
+    if err != nil {
+        return errors.Wrapf(
+            err, "Error while marshalling value for key '%s'", key,
+        )
+    }
+
+    if buf.Len() > 1 {
+        buf.WriteByte(',')
+    }
+    buf.Write(keyBytes)
+    buf.WriteByte(':')
+    buf.Write(valueBytes)
+
+    return nil
+}
+
+// Format outputs the record as a JSON object with the reserved keys first,
+// followed by the record's fields in order. This is done by hand rather than
+// marshalling a map so that the order is preserved.
+func (jsonFormatter) Format(record *Record) ([]byte, error) {
+    buf := new(bytes.Buffer)
+    buf.WriteByte('{')
+
+    names := record.FieldNames.merge(defaultFieldNames)
+    reserved := names.reservedFields(
+        recordTimestamp(record), record.Level, record.Message,
+    )
+    for _, field := range append(reserved, names.callerFields(record)...) {
+        err := writeJSONPair(buf, field.Key, field.Value)
+        if err != nil {
+            return nil, err
+        }
+    }
+    reservedKeys := names.recordReservedKeys(record)
+    for _, field := range record.Fields {
+        err := writeJSONPair(
+            buf, safeFieldKey(field.Key, reservedKeys), field.Value,
+        )
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    buf.WriteByte('}')
+
+    return buf.Bytes(), nil
+}
+
+type standardFormatter struct{}
+
+// Format outputs the timestamp & level followed by the fields and finally the
+// message. Since the reserved parts are positional their names are only used
+// to check whether they've been omitted.
+func (standardFormatter) Format(record *Record) ([]byte, error) {
+    var parts []string
+
+    names := record.FieldNames
+    if names.Timestamp != OmitField {
+        parts = append(parts, recordTimestamp(record).String())
+    }
+    if names.Level != OmitField {
+        parts = append(parts, string(record.Level))
+    }
+
+    callerFields := names.merge(defaultFieldNames).callerFields(record)
+    for _, field := range append(callerFields, record.Fields...) {
+        parts = append(parts, fmt.Sprintf(
+            `%s="%s"`, sanitizeKey(field.Key), fmt.Sprint(field.Value),
+        ))
+    }
+
+    if names.Message != OmitField {
+        parts = append(parts, record.Message)
+    }
+
+    return []byte(strings.Join(parts, " ")), nil
+}
+
+type standardExtendedFormatter struct{}
+
+func (standardExtendedFormatter) Format(record *Record) ([]byte, error) {
+    var keys, values []string
+
+    addColumn := func(key, value string) {
+        keyLen := len(key)
+        valueLen := len(value)
+
+        if keyLen < valueLen {
+            key = padStringRight(key, " ", valueLen - keyLen)
+        } else if keyLen > valueLen {
+            value = padStringRight(value, " ", keyLen - valueLen)
+        }
+
+        keys = append(keys, key)
+        values = append(values, value)
+    }
+
+    names := record.FieldNames.merge(defaultFieldNames)
+    reserved := names.reservedFields(
+        recordTimestamp(record).String(), string(record.Level), record.Message,
+    )
+    for _, field := range reserved {
+        addColumn(sanitizeKey(field.Key), field.Value.(string))
+    }
+    for _, field := range names.callerFields(record) {
+        addColumn(sanitizeKey(field.Key), fmt.Sprint(field.Value))
+    }
+    reservedKeys := names.recordReservedKeys(record)
+    for _, field := range record.Fields {
+        addColumn(
+            sanitizeKey(safeFieldKey(field.Key, reservedKeys)),
+            fmt.Sprint(field.Value),
+        )
+    }
+
+    headerString := strings.Join(keys, " | ")
+    valuesString := strings.Join(values, " | ")
+
+    return []byte(headerString + "\n" + valuesString), nil
+}
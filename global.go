@@ -1,10 +1,14 @@
 package logging
 
 import (
+    "context"
+    stderrors "errors"
     "fmt"
     "io"
     "log"
     "os"
+    "strconv"
+    "strings"
     "sync"
 
     "github.com/pkg/errors"
@@ -20,6 +24,12 @@ var (
 
     globalExtraGenerators []ExtrasGenerator
 
+    globalContextExtractorsRWMutex sync.RWMutex
+    globalContextExtractors []ContextExtractor
+
+    globalFieldNamesRWMutex sync.RWMutex
+    globalFieldNames FieldNames
+
     rootLoggerName string
     initialRootLoggerName = "root"
 
@@ -29,6 +39,10 @@ var (
     }
     RootLoggerLevelEnvVar = prefixEnvVar("ROOT_LOGGER_LEVEL")
     RootLoggerFormatEnvVar = prefixEnvVar("ROOT_LOGGER_FORMAT")
+    RootLoggerTimestampFormatEnvVar = prefixEnvVar(
+        "ROOT_LOGGER_TIMESTAMP_FORMAT",
+    )
+    RootLoggerTimestampUTCEnvVar = prefixEnvVar("ROOT_LOGGER_TIMESTAMP_UTC")
 )
 
 // GetGlobalExtras returns the global extras.
@@ -52,6 +66,44 @@ func AddGlobalExtras(extras ...ExtrasGenerator) {
     globalExtraGenerators = append(globalExtraGenerators, extras...)
 }
 
+// GetGlobalContextExtractors returns the global context extractors.
+func GetGlobalContextExtractors() []ContextExtractor {
+    globalContextExtractorsRWMutex.RLock()
+    defer globalContextExtractorsRWMutex.RUnlock()
+    return globalContextExtractors
+}
+
+// SetGlobalContextExtractors sets the context extractors run by every logger
+// when logging with a context.
+func SetGlobalContextExtractors(extractors ...ContextExtractor) {
+    globalContextExtractorsRWMutex.Lock()
+    defer globalContextExtractorsRWMutex.Unlock()
+    globalContextExtractors = extractors
+}
+
+// AddGlobalContextExtractors appends the provided extractors to the global
+// context extractors.
+func AddGlobalContextExtractors(extractors ...ContextExtractor) {
+    globalContextExtractorsRWMutex.Lock()
+    defer globalContextExtractorsRWMutex.Unlock()
+    globalContextExtractors = append(globalContextExtractors, extractors...)
+}
+
+// GetGlobalFieldNames returns the global FieldNames.
+func GetGlobalFieldNames() FieldNames {
+    globalFieldNamesRWMutex.RLock()
+    defer globalFieldNamesRWMutex.RUnlock()
+    return globalFieldNames
+}
+
+// SetGlobalFieldNames sets the FieldNames used by every logger for any names
+// the logger hasn't set itself.
+func SetGlobalFieldNames(fieldNames FieldNames) {
+    globalFieldNamesRWMutex.Lock()
+    defer globalFieldNamesRWMutex.Unlock()
+    globalFieldNames = fieldNames
+}
+
 // GetLogger get an existing logger by its identifier.
 func GetLogger(identifier string) *Logger {
     loggersRWMutex.RLock()
@@ -111,7 +163,17 @@ func Debug(message string, extras ...Extras) {
     loggersRWMutex.RLock()
     defer loggersRWMutex.RUnlock()
 
-    logger.Debug(message, extras...)
+    logger.logToLevel(0, DEBUG, message, extras)
+}
+
+// Trace uses the root logger to log to trace level.
+func Trace(message string, extras ...Extras) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(0, TRACE, message, extras)
 }
 
 // Warn uses the root logger to log to warn level.
@@ -121,7 +183,7 @@ func Warn(message string, extras ...Extras) {
     loggersRWMutex.RLock()
     defer loggersRWMutex.RUnlock()
 
-    logger.Warn(message, extras...)
+    logger.logToLevel(0, WARN, message, extras)
 }
 
 // Error uses the root logger to log to error level.
@@ -131,7 +193,7 @@ func Error(message string, extras ...Extras) {
     loggersRWMutex.RLock()
     defer loggersRWMutex.RUnlock()
 
-    logger.Error(message, extras...)
+    logger.logToLevel(0, ERROR, message, extras)
 }
 
 // Info uses the root logger to log to info level.
@@ -141,7 +203,29 @@ func Info(message string, extras ...Extras) {
     loggersRWMutex.RLock()
     defer loggersRWMutex.RUnlock()
 
-    logger.Info(message, extras...)
+    logger.logToLevel(0, INFO, message, extras)
+}
+
+// Fatal uses the root logger to log to fatal level and then exits the
+// program.
+func Fatal(message string, extras ...Extras) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.fatal(0, message, extras)
+}
+
+// Panic uses the root logger to log to panic level and then panics.
+func Panic(message string, extras ...Extras) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(0, PANIC, message, extras)
+    panic(message)
 }
 
 // Exception uses the root logger to log an error at error level.
@@ -151,7 +235,190 @@ func Exception(err error, message string, extras ...Extras) {
     loggersRWMutex.RLock()
     defer loggersRWMutex.RUnlock()
 
-    logger.Exception(err, message, extras...)
+    logger.exception(nil, 0, err, message, extras)
+}
+
+// Tracef uses the root logger to log a formatted message to trace level.
+func Tracef(format string, args ...interface{}) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(0, TRACE, fmt.Sprintf(format, args...), nil)
+}
+
+// Tracew uses the root logger to log to trace level with extras provided as
+// alternating keys and values.
+func Tracew(message string, keysAndValues ...interface{}) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(
+        0, TRACE, message, extrasFromKeysAndValues(keysAndValues),
+    )
+}
+
+// Debugf uses the root logger to log a formatted message to debug level.
+func Debugf(format string, args ...interface{}) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(0, DEBUG, fmt.Sprintf(format, args...), nil)
+}
+
+// Debugw uses the root logger to log to debug level with extras provided as
+// alternating keys and values.
+func Debugw(message string, keysAndValues ...interface{}) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(
+        0, DEBUG, message, extrasFromKeysAndValues(keysAndValues),
+    )
+}
+
+// Infof uses the root logger to log a formatted message to info level.
+func Infof(format string, args ...interface{}) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(0, INFO, fmt.Sprintf(format, args...), nil)
+}
+
+// Infow uses the root logger to log to info level with extras provided as
+// alternating keys and values.
+func Infow(message string, keysAndValues ...interface{}) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(
+        0, INFO, message, extrasFromKeysAndValues(keysAndValues),
+    )
+}
+
+// Warnf uses the root logger to log a formatted message to warn level.
+func Warnf(format string, args ...interface{}) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(0, WARN, fmt.Sprintf(format, args...), nil)
+}
+
+// Warnw uses the root logger to log to warn level with extras provided as
+// alternating keys and values.
+func Warnw(message string, keysAndValues ...interface{}) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(
+        0, WARN, message, extrasFromKeysAndValues(keysAndValues),
+    )
+}
+
+// Errorf uses the root logger to log a formatted message to error level.
+func Errorf(format string, args ...interface{}) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(0, ERROR, fmt.Sprintf(format, args...), nil)
+}
+
+// Errorw uses the root logger to log to error level with extras provided as
+// alternating keys and values.
+func Errorw(message string, keysAndValues ...interface{}) {
+    logger := GetRootLogger()
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevel(
+        0, ERROR, message, extrasFromKeysAndValues(keysAndValues),
+    )
+}
+
+// TraceContext uses the logger carried by ctx (or the root logger if there
+// isn't one) to log to trace level.
+func TraceContext(ctx context.Context, message string, extras ...Extras) {
+    logger := FromContext(ctx)
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevelContext(ctx, 0, TRACE, message, extras)
+}
+
+// DebugContext uses the logger carried by ctx (or the root logger if there
+// isn't one) to log to debug level.
+func DebugContext(ctx context.Context, message string, extras ...Extras) {
+    logger := FromContext(ctx)
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevelContext(ctx, 0, DEBUG, message, extras)
+}
+
+// InfoContext uses the logger carried by ctx (or the root logger if there
+// isn't one) to log to info level.
+func InfoContext(ctx context.Context, message string, extras ...Extras) {
+    logger := FromContext(ctx)
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevelContext(ctx, 0, INFO, message, extras)
+}
+
+// WarnContext uses the logger carried by ctx (or the root logger if there
+// isn't one) to log to warn level.
+func WarnContext(ctx context.Context, message string, extras ...Extras) {
+    logger := FromContext(ctx)
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevelContext(ctx, 0, WARN, message, extras)
+}
+
+// ErrorContext uses the logger carried by ctx (or the root logger if there
+// isn't one) to log to error level.
+func ErrorContext(ctx context.Context, message string, extras ...Extras) {
+    logger := FromContext(ctx)
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.logToLevelContext(ctx, 0, ERROR, message, extras)
+}
+
+// ExceptionContext uses the logger carried by ctx (or the root logger if
+// there isn't one) to log an error at error level.
+func ExceptionContext(
+    ctx context.Context, err error, message string, extras ...Extras,
+) {
+    logger := FromContext(ctx)
+
+    loggersRWMutex.RLock()
+    defer loggersRWMutex.RUnlock()
+
+    logger.exception(ctx, 0, err, message, extras)
 }
 
 func addLogger(identifier string, logger *Logger) error {
@@ -172,10 +439,81 @@ func addLogger(identifier string, logger *Logger) error {
     return nil
 }
 
-func removeLogger(identifier string) {
+// Shutdown flushes the writers of every registered logger and closes any
+// writers they own so that nothing logged is lost when the program exits. The
+// loggers stay registered so logging to their other writers still works. If
+// ctx is done before everything has been flushed & closed its error is
+// returned.
+func Shutdown(ctx context.Context) error {
+    loggersRWMutex.RLock()
+    loggers := make([]*Logger, 0, len(allLoggers))
+    for _, logger := range allLoggers {
+        loggers = append(loggers, logger)
+    }
+    loggersRWMutex.RUnlock()
+
+    done := make(chan error, 1)
+    go func() {
+        done <- shutdownLoggers(loggers)
+    }()
+
+    select {
+    case err := <-done:
+        return err
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
+
+// shutdownLoggers flushes every writer and then closes every owned writer of
+// the provided loggers. Writers shared between loggers are only flushed and
+// closed once.
+func shutdownLoggers(loggers []*Logger) error {
+    writers := make(map[io.Writer]bool)
+    var ownedWriters []io.Closer
+    seenOwned := make(map[io.Closer]bool)
+    for _, logger := range loggers {
+        state := logger.loadState()
+        logger.reportDropped(state)
+        for writer := range state.sinks {
+            writers[writer] = true
+        }
+        for _, writer := range state.ownedWriters {
+            if !seenOwned[writer] {
+                seenOwned[writer] = true
+                ownedWriters = append(ownedWriters, writer)
+            }
+        }
+    }
+
+    var errs []error
+    for writer := range writers {
+        if err := flushWriter(writer); err != nil {
+            errs = append(errs, err)
+        }
+    }
+    if err := closeWriters(ownedWriters); err != nil {
+        errs = append(errs, err)
+    }
+    if err := stderrors.Join(errs...); err != nil {
+        return errors.Wrap(err, "Error while shutting down loggers")
+    }
+
+    return nil
+}
+
+// removeLogger removes the provided logger from the registry if it is the
+// logger registered under its identifier and reports whether it was.
+func removeLogger(logger *Logger) bool {
     loggersRWMutex.Lock()
     defer loggersRWMutex.Unlock()
-    delete(allLoggers, identifier)
+    if allLoggers[logger.identifier] != logger {
+        return false
+    }
+
+    delete(allLoggers, logger.identifier)
+
+    return true
 }
 
 func identifierExists(identifier string) bool {
@@ -211,15 +549,41 @@ func init() {
             if envFormat == UnknownFormat {
                 tempDebugLog(fmt.Sprintf(
                     "Found value for '%s' but it's value '%s' was not an " +
-                        "understood format.",
+                        "understood format; it will be used if a format " +
+                        "is registered with that name.",
                     RootLoggerFormatEnvVar,
                     formatString,
                 ))
+                formatsRWMutex.Lock()
+                pendingRootFormat = strings.ToLower(formatString)
+                formatsRWMutex.Unlock()
             } else {
                 format = envFormat
             }
         }
 
+        timestampFormat := DefaultTimestampFormat
+        if timestampFormatString, ok := os.LookupEnv(
+            RootLoggerTimestampFormatEnvVar,
+        ); ok {
+            timestampFormat = TimestampFormatFromString(timestampFormatString)
+        }
+
+        utc := false
+        if utcString, ok := os.LookupEnv(RootLoggerTimestampUTCEnvVar); ok {
+            envUTC, err := strconv.ParseBool(utcString)
+            if err != nil {
+                tempDebugLog(fmt.Sprintf(
+                    "Found value for '%s' but it's value '%s' was not a " +
+                        "boolean.",
+                    RootLoggerTimestampUTCEnvVar,
+                    utcString,
+                ))
+            } else {
+                utc = envUTC
+            }
+        }
+
         loggersRWMutex = new(sync.RWMutex)
         globalExtraGeneratorsMutex = new(sync.RWMutex)
         rootLoggerRWMutex = new(sync.RWMutex)
@@ -227,19 +591,20 @@ func init() {
         rootLoggerRWMutex.Lock()
         rootLoggerName = initialRootLoggerName
 
-        logLevelsEnabled, err := logsEnabledFromLevel(logLevel)
+        minSeverity, err := severityForLevel(logLevel)
         if err != nil {
             rootLoggerRWMutex.Unlock()
             panic(err)
         }
-        rootLogger := &Logger{
-            identifier: rootLoggerName,
-            format: format,
-            writerLoggers: map[io.Writer]*log.Logger{
-                os.Stdout: log.New(os.Stdout, "", 0),
+        rootLogger := newLoggerWithState(rootLoggerName, &loggerState{
+            formatter: GetFormatter(format),
+            sinks: map[io.Writer]*sink{
+                os.Stdout: {logger: log.New(os.Stdout, "", 0)},
             },
-            logLevelsEnabled: logLevelsEnabled,
-        }
+            minSeverity: minSeverity,
+            timestampFormat: timestampFormat,
+            utc: utc,
+        })
 
         loggersRWMutex.Lock()
         allLoggers = map[string]*Logger{
@@ -2,11 +2,14 @@
 package logging
 
 import (
+    "context"
+    "runtime"
     "math/rand"
     "os"
     "strconv"
     "strings"
     "testing"
+    "time"
     "errors"
 
     gm "github.com/onsi/gomega"
@@ -53,7 +56,7 @@ func TestSetRootLogger(t *testing.T) {
     Info("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
     ))
     g.Expect(builder2.String()).To(gm.BeEmpty())
 
@@ -67,7 +70,7 @@ func TestSetRootLogger(t *testing.T) {
     g.Expect(err).ToNot(gm.HaveOccurred())
     g.Expect(builder.String()).To(gm.BeEmpty())
     g.Expect(builder2.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Bar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Bar"}`,
     ))
 }
 
@@ -85,8 +88,9 @@ func TestGlobalException(t *testing.T) {
     Exception(errors.New("Test err"), "Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"error":"Test err[^"]+","log_level":"ERROR",` +
-            `"message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"ERROR","message":"Foo",` +
+            `"error":{"message":"Test err","type":"\*errors.errorString",` +
+            `"stack":\[{"file":"[^"]+/global_test\.go",`,
     ))
 }
 
@@ -105,7 +109,7 @@ func TestGlobalDebug(t *testing.T) {
     Debug("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"DEBUG","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"DEBUG","message":"Foo"}`,
     ))
 }
 
@@ -124,7 +128,7 @@ func TestGlobalWarn(t *testing.T) {
     Warn("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"WARN","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"WARN","message":"Foo"}`,
     ))
 }
 
@@ -143,7 +147,7 @@ func TestGlobalError(t *testing.T) {
     Error("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"ERROR","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"ERROR","message":"Foo"}`,
     ))
 }
 
@@ -162,7 +166,7 @@ func TestGlobalInfo(t *testing.T) {
     Info("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
     ))
 }
 
@@ -186,7 +190,7 @@ func TestSetDefaultLoggerLogLevel(t *testing.T) {
     Debug("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"DEBUG","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"DEBUG","message":"Foo"}`,
     ))
 }
 
@@ -213,7 +217,7 @@ func TestAddGlobalExtrasBasic(t *testing.T) {
     Debug("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"DEBUG","message":"Foo","test":"bar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"DEBUG","message":"Foo","test":"bar"}`,
     ))
 }
 
@@ -246,7 +250,7 @@ func TestAddGlobalExtrasFunc(t *testing.T) {
     Debug("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"DEBUG","message":"Foo","test":"baz","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"DEBUG","message":"Foo","test":"baz"}`,
     ))
 
     builder.Reset()
@@ -256,7 +260,7 @@ func TestAddGlobalExtrasFunc(t *testing.T) {
     Debug("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"DEBUG","message":"Foo","test":"baz2","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"DEBUG","message":"Foo","test":"baz2"}`,
     ))
 }
 
@@ -284,3 +288,155 @@ func TestSetGetGlobalExtras(t *testing.T) {
     t.Log(extra)
     g.Expect(globalExtras).To(gm.HaveLen(1))
 }
+
+func TestGlobalTrace(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+    var builder strings.Builder
+    rootLogger := GetRootLogger()
+    rootLogger.SetWriters(&builder)
+    err := rootLogger.SetLogLevel(TRACE)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer func() {
+        rootLogger.SetWriters(os.Stdout)
+        _ = rootLogger.SetLogLevel(INFO)
+    }()
+
+    Trace("Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"TRACE","message":"Foo"}`,
+    ))
+}
+
+func TestGlobalPanic(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+    var builder strings.Builder
+    rootLogger := GetRootLogger()
+    rootLogger.SetWriters(&builder)
+    defer func() {
+        rootLogger.SetWriters(os.Stdout)
+    }()
+
+    g.Expect(func() {
+        Panic("Foo")
+    }).To(gm.Panic())
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"PANIC","message":"Foo"}`,
+    ))
+}
+
+func TestGlobalFormatted(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+    var builder strings.Builder
+    rootLogger := GetRootLogger()
+    rootLogger.SetWriters(&builder)
+    defer func() {
+        rootLogger.SetWriters(os.Stdout)
+    }()
+
+    Infof("Foo %s", "bar")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo bar"}`,
+    ))
+}
+
+func TestGlobalKeysAndValues(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+    var builder strings.Builder
+    rootLogger := GetRootLogger()
+    rootLogger.SetWriters(&builder)
+    defer func() {
+        rootLogger.SetWriters(os.Stdout)
+    }()
+
+    Errorw("Foo", "bar", 1)
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"ERROR","message":"Foo","bar":1}`,
+    ))
+}
+
+func TestGlobalCaller(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+    var builder strings.Builder
+    rootLogger := GetRootLogger()
+    rootLogger.SetWriters(&builder)
+    rootLogger.SetCaller(true)
+    defer func() {
+        rootLogger.SetWriters(os.Stdout)
+        rootLogger.SetCaller(false)
+    }()
+
+    _, _, line, _ := runtime.Caller(0)
+    Info("Foo")
+    Infof("Foo")
+    Infow("Foo")
+    InfoContext(context.Background(), "Foo")
+    Exception(errors.New("Bar"), "Foo")
+
+    lines := strings.Split(strings.TrimSpace(builder.String()), "\n")
+    g.Expect(lines).To(gm.HaveLen(5))
+    for i, logLine := range lines {
+        g.Expect(logLine).To(gm.MatchRegexp(
+            `"caller":{"file":"[^"]+/global_test\.go","line":%d,`,
+            line + i + 1,
+        ))
+    }
+}
+
+func TestShutdown(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    owned := new(trackingWriter)
+    asyncBuilder := new(lockedBuilder)
+    asyncWriter := NewAsyncWriter(asyncBuilder, 10, Block)
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithOwnedWriters(owned),
+        WithLogWriters(asyncWriter),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo")
+
+    ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+    defer cancel()
+    g.Expect(Shutdown(ctx)).To(gm.Succeed())
+
+    g.Expect(asyncBuilder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
+    ))
+    g.Expect(owned.closed).To(gm.Equal(1))
+    g.Expect(GetLogger(newLogger.Identifier())).To(
+        gm.BeIdenticalTo(newLogger),
+    )
+}
+
+func TestShutdownDeadline(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    writer := newBlockingWriter()
+    asyncWriter := NewAsyncWriter(writer, 10, Block)
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(asyncWriter),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer func() {
+        close(writer.release)
+        newLogger.Close()
+        asyncWriter.Close()
+    }()
+
+    newLogger.Info("Foo")
+
+    ctx, cancel := context.WithTimeout(
+        context.Background(), 10 * time.Millisecond,
+    )
+    defer cancel()
+    g.Expect(Shutdown(ctx)).To(gm.Equal(context.DeadlineExceeded))
+}
@@ -1,6 +1,14 @@
 module github.com/daihasso/slogging
 
+go 1.21
+
 require (
 	github.com/onsi/gomega v1.4.3
 	github.com/pkg/errors v0.8.1
 )
+
+require (
+	golang.org/x/net v0.0.0-20180906233101-161cd47e91fd // indirect
+	golang.org/x/text v0.3.0 // indirect
+	gopkg.in/yaml.v2 v2.2.1 // indirect
+)
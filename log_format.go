@@ -2,6 +2,9 @@ package logging
 
 import (
     "strings"
+    "sync"
+
+    "github.com/pkg/errors"
 )
 
 // LogFormat is a representation of what format a log should output.
@@ -14,17 +17,93 @@ const (
     JSON
     Standard
     StandardExtended
+    Logfmt
+    Console
+)
+
+var (
+    formatsRWMutex sync.RWMutex
+    formatters = map[LogFormat]Formatter{
+        JSON: JSONFormatter,
+        Standard: StandardFormatter,
+        StandardExtended: StandardExtendedFormatter,
+        Logfmt: LogfmtFormatter,
+        Console: ConsoleFormatter,
+    }
+    formatNames = map[string]LogFormat{
+        "json": JSON,
+        "standard": Standard,
+        "standardextended": StandardExtended,
+        "logfmt": Logfmt,
+        "console": Console,
+    }
+    nextFormat = Console + 1
+
+    // pendingRootFormat is the name of a format requested for the root logger
+    // via the environment which wasn't registered at init time.
+    pendingRootFormat string
 )
 
+// FormatFromString gets the LogFormat registered under the provided name
+// (case-insensitive) or UnknownFormat if there isn't one.
 func FormatFromString(format string) LogFormat {
-    switch strings.ToLower(format) {
-    case "json":
-        return JSON
-    case "standard":
-        return Standard
-    case "standardextended":
-        return StandardExtended
-    default:
-        return UnknownFormat
+    formatsRWMutex.RLock()
+    defer formatsRWMutex.RUnlock()
+    if logFormat, ok := formatNames[strings.ToLower(format)]; ok {
+        return logFormat
+    }
+
+    return UnknownFormat
+}
+
+// GetFormatter gets the Formatter for the provided LogFormat or nil if the
+// format is unknown.
+func GetFormatter(logFormat LogFormat) Formatter {
+    formatsRWMutex.RLock()
+    defer formatsRWMutex.RUnlock()
+    return formatters[logFormat]
+}
+
+// RegisterFormat registers a custom Formatter under the provided name and
+// returns a new LogFormat for it. Once registered the name can be used with
+// FormatFromString and the root logger format environment variable.
+func RegisterFormat(name string, formatter Formatter) (LogFormat, error) {
+    if name == "" {
+        return UnknownFormat, errors.New("Format name cannot be empty")
+    }
+    if formatter == nil {
+        return UnknownFormat, errors.New("Formatter cannot be nil")
+    }
+
+    lowerName := strings.ToLower(name)
+
+    formatsRWMutex.Lock()
+    if _, ok := formatNames[lowerName]; ok {
+        formatsRWMutex.Unlock()
+        return UnknownFormat, errors.Errorf(
+            "Format with name '%s' already registered", name,
+        )
     }
+
+    logFormat := nextFormat
+    nextFormat++
+    formatters[logFormat] = formatter
+    formatNames[lowerName] = logFormat
+
+    applyToRoot := pendingRootFormat == lowerName
+    if applyToRoot {
+        pendingRootFormat = ""
+    }
+    formatsRWMutex.Unlock()
+
+    // NOTE: The root logger is configured during init which will always run
+    //       before any user code gets a chance to register formats, so honour
+    //       the environment now that the format exists.
+    if applyToRoot {
+        if rootLogger := GetLogger(initialRootLoggerName); rootLogger != nil {
+            rootLogger.SetFormatter(formatter)
+        }
+    }
+
+    return logFormat, nil
 }
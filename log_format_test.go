@@ -0,0 +1,113 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "math/rand"
+    "os"
+    "strconv"
+    "strings"
+    "testing"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestFormatFromString(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    g.Expect(FormatFromString("JSON")).To(gm.Equal(JSON))
+    g.Expect(FormatFromString("standard")).To(gm.Equal(Standard))
+    g.Expect(FormatFromString("StandardExtended")).To(
+        gm.Equal(StandardExtended),
+    )
+    g.Expect(FormatFromString("nonsense")).To(gm.Equal(UnknownFormat))
+}
+
+func TestRegisterFormat(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    name := "custom" + strconv.Itoa(rand.Int())
+    customFormat, err := RegisterFormat(name, FormatterFunc(
+        func(record *Record) ([]byte, error) {
+            return []byte(string(record.Level) + ": " + record.Message), nil
+        },
+    ))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(FormatFromString(strings.ToUpper(name))).To(
+        gm.Equal(customFormat),
+    )
+
+    _, err = RegisterFormat(name, JSONFormatter)
+    g.Expect(err).To(gm.HaveOccurred())
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(customFormat),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo")
+
+    g.Expect(builder.String()).To(gm.Equal("INFO: Foo\n"))
+}
+
+func TestRegisterFormatPendingRoot(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    rootLogger := GetRootLogger()
+    rootLogger.SetWriters(&builder)
+    defer func() {
+        rootLogger.SetWriters(os.Stdout)
+        rootLogger.SetFormat(JSON)
+    }()
+
+    name := "pending" + strconv.Itoa(rand.Int())
+    formatsRWMutex.Lock()
+    pendingRootFormat = name
+    formatsRWMutex.Unlock()
+
+    _, err := RegisterFormat(name, FormatterFunc(
+        func(record *Record) ([]byte, error) {
+            return []byte("pending " + record.Message), nil
+        },
+    ))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    Info("Foo")
+
+    g.Expect(builder.String()).To(gm.Equal("pending Foo\n"))
+}
+
+func TestWithFormatter(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormatter(FormatterFunc(
+            func(record *Record) ([]byte, error) {
+                return []byte(record.Message + "!"), nil
+            },
+        )),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo")
+
+    g.Expect(builder.String()).To(gm.Equal("Foo!\n"))
+}
+
+func TestWithFormatUnknown(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    _, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithFormat(UnknownFormat),
+    )
+    g.Expect(err).To(gm.HaveOccurred())
+}
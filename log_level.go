@@ -3,6 +3,10 @@ package logging
 import (
     "fmt"
     "strings"
+    "sync"
+    "sync/atomic"
+
+    "github.com/pkg/errors"
 )
 
 // LogLevel is a representation of the logging level for a logger.
@@ -12,12 +16,93 @@ type LogLevel string
 // Definition of LogLevels for a logger.
 const (
     UnsetLogLevel LogLevel = ""
+    PANIC LogLevel = "PANIC"
+    FATAL LogLevel = "FATAL"
     ERROR LogLevel = "ERROR"
     WARN LogLevel = "WARN"
     INFO LogLevel = "INFO"
     DEBUG LogLevel = "DEBUG"
+    TRACE LogLevel = "TRACE"
+)
+
+var (
+    // levelsMutex serialises registrations; lookups read levelSeverities
+    // without locking.
+    levelsMutex sync.Mutex
+    // levelSeverities holds a map[LogLevel]int which is replaced (never
+    // modified) when a new level is registered. It's initialized here rather
+    // than in an init function so it's ready before the root logger is made.
+    levelSeverities = func() *atomic.Value {
+        severities := new(atomic.Value)
+        // NOTE: These line up with the levels from the standard library's
+        //       log/slog package.
+        severities.Store(map[LogLevel]int{
+            TRACE: -8,
+            DEBUG: -4,
+            INFO: 0,
+            WARN: 4,
+            ERROR: 8,
+            FATAL: 12,
+            PANIC: 16,
+        })
+        return severities
+    }()
 )
 
+func loadLevelSeverities() map[LogLevel]int {
+    return levelSeverities.Load().(map[LogLevel]int)
+}
+
+// RegisterLogLevel registers a custom LogLevel with the provided severity. A
+// logger set to a level will log anything with a severity greater than or
+// equal to that level's severity. For reference the built-in levels have the
+// severities:
+//   TRACE: -8, DEBUG: -4, INFO: 0, WARN: 4, ERROR: 8, FATAL: 12, PANIC: 16
+func RegisterLogLevel(logLevel LogLevel, severity int) (LogLevel, error) {
+    logLevel = LogLevel(strings.ToUpper(string(logLevel)))
+    if logLevel == UnsetLogLevel {
+        return UnsetLogLevel, errors.New("LogLevel cannot be empty")
+    }
+
+    levelsMutex.Lock()
+    defer levelsMutex.Unlock()
+
+    existing := loadLevelSeverities()
+    if _, ok := existing[logLevel]; ok {
+        return UnsetLogLevel, errors.Errorf(
+            "LogLevel '%s' is already registered", logLevel,
+        )
+    }
+
+    newSeverities := make(map[LogLevel]int, len(existing) + 1)
+    for level, levelSeverity := range existing {
+        newSeverities[level] = levelSeverity
+    }
+    newSeverities[logLevel] = severity
+    levelSeverities.Store(newSeverities)
+
+    return logLevel, nil
+}
+
+// Severity gets the numeric severity of this LogLevel and whether it is a
+// known (built-in or registered) level.
+func (self LogLevel) Severity() (int, bool) {
+    severity, ok := loadLevelSeverities()[self]
+    return severity, ok
+}
+
+func severityForLevel(logLevel LogLevel) (int, error) {
+    severity, ok := logLevel.Severity()
+    if !ok {
+        return 0, fmt.Errorf(
+            "Incorrect LogLevel: '%s'",
+            logLevel,
+        )
+    }
+
+    return severity, nil
+}
+
 // GetLogLevelsForString will get the appropriate loglevels for a string
 // log level representation.
 func GetLogLevelsForString(logLevel string) (map[LogLevel]bool, error) {
@@ -25,24 +110,16 @@ func GetLogLevelsForString(logLevel string) (map[LogLevel]bool, error) {
 }
 
 func logsEnabledFromLevel(logLevel LogLevel) (map[LogLevel]bool, error) {
+    minSeverity, err := severityForLevel(logLevel)
+    if err != nil {
+        return nil, err
+    }
+
     logLevels := make(map[LogLevel]bool)
-    switch logLevel {
-    case DEBUG:
-        logLevels[DEBUG] = true
-        fallthrough
-    case INFO:
-        logLevels[INFO] = true
-        fallthrough
-    case WARN:
-        logLevels[WARN] = true
-        fallthrough
-    case ERROR:
-        logLevels[ERROR] = true
-    default:
-        return nil, fmt.Errorf(
-            "Incorrect LogLevel: '%s'",
-            logLevel,
-        )
+    for level, severity := range loadLevelSeverities() {
+        if severity >= minSeverity {
+            logLevels[level] = true
+        }
     }
 
     return logLevels, nil
@@ -1,6 +1,10 @@
+/* #nosec G404 */
 package logging
 
 import (
+    "math/rand"
+    "strconv"
+    "strings"
     "testing"
 
     gm "github.com/onsi/gomega"
@@ -25,3 +29,97 @@ func TestLogsEnabledForString(t *testing.T) {
 
     g.Expect(logLevel).To(gm.Equal(logsForInfo))
 }
+
+func TestLogsEnabledExtendedLevels(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    logsForTrace, err := GetLogLevelsForString("trace")
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    for _, level := range []LogLevel{
+        TRACE, DEBUG, INFO, WARN, ERROR, FATAL, PANIC,
+    } {
+        g.Expect(logsForTrace).To(gm.HaveKeyWithValue(level, true))
+    }
+
+    logsForError, err := logsEnabledFromLevel(ERROR)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(logsForError).To(gm.Equal(map[LogLevel]bool{
+        ERROR: true,
+        FATAL: true,
+        PANIC: true,
+    }))
+
+    logsForPanic, err := logsEnabledFromLevel(LogLevelFromString("panic"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(logsForPanic).To(gm.Equal(map[LogLevel]bool{PANIC: true}))
+}
+
+func TestRegisterLogLevel(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    notice, err := RegisterLogLevel(
+        LogLevel("notice" + strconv.Itoa(rand.Int())), 2,
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(string(notice)).To(gm.Equal(strings.ToUpper(string(notice))))
+
+    severity, ok := notice.Severity()
+    g.Expect(ok).To(gm.BeTrue())
+    g.Expect(severity).To(gm.Equal(2))
+
+    _, err = RegisterLogLevel(notice, 3)
+    g.Expect(err).To(gm.HaveOccurred())
+    _, err = RegisterLogLevel(INFO, 3)
+    g.Expect(err).To(gm.HaveOccurred())
+
+    logsForNotice, err := logsEnabledFromLevel(notice)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(logsForNotice).To(gm.HaveKey(notice))
+    g.Expect(logsForNotice).To(gm.HaveKey(WARN))
+    g.Expect(logsForNotice).ToNot(gm.HaveKey(INFO))
+
+    _, ok = LogLevel("NONSENSE").Severity()
+    g.Expect(ok).To(gm.BeFalse())
+}
+
+func TestLoggerLogAtCustomLevel(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    audit, err := RegisterLogLevel(
+        LogLevel("audit" + strconv.Itoa(rand.Int())), 2,
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithLogLevel(audit),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo")
+    g.Expect(builder.String()).To(gm.BeEmpty())
+
+    newLogger.LogAt(audit, "Foo", Extra("bar", "baz"))
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"` + string(audit) +
+            `","message":"Foo","bar":"baz"}`,
+    ))
+
+    builder.Reset()
+    err = newLogger.SetLogLevel(WARN)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    newLogger.LogAt(audit, "Foo")
+    g.Expect(builder.String()).To(gm.BeEmpty())
+
+    newLogger.LogAt(LogLevel("NONSENSE"), "Foo")
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"ERROR",` +
+            `"message":"Error while logging at unknown level.",` +
+            `"error":{"message":"Incorrect LogLevel: 'NONSENSE'",`,
+    ))
+}
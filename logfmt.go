@@ -0,0 +1,115 @@
+package logging
+
+import (
+    "bytes"
+    "encoding"
+    "fmt"
+    "strconv"
+    "strings"
+    "unicode"
+    "unicode/utf8"
+)
+
+// Reserved keys used by the Logfmt format.
+const (
+    logfmtTimestampKey = "ts"
+    logfmtLevelKey = "level"
+    logfmtMessageKey = "msg"
+)
+
+// LogfmtFormatter is the built-in Formatter backing the Logfmt LogFormat.
+var LogfmtFormatter Formatter = logfmtFormatter{}
+
+type logfmtFormatter struct{}
+
+// Format outputs the record as a logfmt line:
+//   ts=2019-03-09T14:59:50.123456-08:00 level=info msg="Hello world!" key=value
+// Values are quoted & escaped only when required.
+func (logfmtFormatter) Format(record *Record) ([]byte, error) {
+    buf := new(bytes.Buffer)
+
+    names := record.FieldNames.merge(logfmtFieldNames)
+    reserved := names.reservedFields(
+        recordTimestamp(record).withDefault(RFC3339Nano).String(),
+        strings.ToLower(string(record.Level)),
+        record.Message,
+    )
+    for _, field := range reserved {
+        writeLogfmtPair(buf, field.Key, field.Value.(string))
+    }
+    for _, field := range names.callerFields(record) {
+        writeLogfmtPair(buf, field.Key, logfmtValueString(field.Value))
+    }
+    reservedKeys := names.recordReservedKeys(record)
+    for _, field := range record.Fields {
+        writeLogfmtPair(
+            buf,
+            safeFieldKey(field.Key, reservedKeys),
+            logfmtValueString(field.Value),
+        )
+    }
+
+    return buf.Bytes(), nil
+}
+
+func writeLogfmtPair(buf *bytes.Buffer, key, value string) {
+    if buf.Len() != 0 {
+        buf.WriteByte(' ')
+    }
+    buf.WriteString(logfmtKey(key))
+    buf.WriteByte('=')
+    if logfmtNeedsQuoting(value) {
+        buf.WriteString(strconv.Quote(value))
+    } else {
+        buf.WriteString(value)
+    }
+}
+
+// logfmtKey replaces any characters that aren't allowed in a logfmt key with
+// underscores.
+func logfmtKey(key string) string {
+    if key == "" {
+        return "_"
+    }
+
+    return strings.Map(func(r rune) rune {
+        if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError ||
+            !unicode.IsPrint(r) {
+            return '_'
+        }
+        return r
+    }, key)
+}
+
+func logfmtNeedsQuoting(value string) bool {
+    if value == "" {
+        return true
+    }
+    for _, r := range value {
+        if r <= ' ' || r == '=' || r == '"' || r == '\\' ||
+            r == utf8.RuneError || !unicode.IsPrint(r) {
+            return true
+        }
+    }
+
+    return false
+}
+
+func logfmtValueString(value interface{}) string {
+    switch typedValue := value.(type) {
+    case nil:
+        return "null"
+    case string:
+        return typedValue
+    case error:
+        return typedValue.Error()
+    case encoding.TextMarshaler:
+        text, err := typedValue.MarshalText()
+        if err != nil {
+            return fmt.Sprintf("!ERROR:%v", err)
+        }
+        return string(text)
+    default:
+        return fmt.Sprint(value)
+    }
+}
@@ -0,0 +1,87 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "errors"
+    "math/rand"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestLogfmtFormatter(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    record := &Record{
+        Time: time.Date(2019, 3, 9, 14, 59, 50, 123000000, time.UTC),
+        Level: WARN,
+        Message: `Hello "world"`,
+        Fields: []Field{
+            {Key: "simple", Value: "value"},
+            {Key: "spaced", Value: "a value"},
+            {Key: "equals", Value: "a=b"},
+            {Key: "empty", Value: ""},
+            {Key: "number", Value: 5},
+            {Key: "nothing", Value: nil},
+            {Key: "err", Value: errors.New("line1\nline2")},
+            {Key: "bad key=", Value: `back\slash`},
+            {Key: "msg", Value: "clash"},
+        },
+    }
+
+    result, err := LogfmtFormatter.Format(record)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(string(result)).To(gm.Equal(
+        `ts=2019-03-09T14:59:50.123Z level=warn msg="Hello \"world\"" ` +
+            `simple=value spaced="a value" equals="a=b" empty="" ` +
+            `number=5 nothing=null err="line1\nline2" ` +
+            `bad_key_="back\\slash" fields.msg=clash`,
+    ))
+}
+
+func TestLoggerLogfmt(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    g.Expect(FormatFromString("logfmt")).To(gm.Equal(Logfmt))
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(Logfmt),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo bar", Extra("test", "baz"))
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^ts=[^\s]+ level=info msg="Foo bar" test=baz\n$`,
+    ))
+}
+
+func TestLogfmtFormatterCaller(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    record := &Record{
+        Time: time.Date(2019, 3, 9, 14, 59, 50, 0, time.UTC),
+        Level: INFO,
+        Message: "Foo",
+        Caller: &Caller{
+            File: "/src/foo/bar.go",
+            Line: 12,
+            Function: "example.com/foo.Bar",
+        },
+        Fields: []Field{{Key: "caller", Value: "clash"}},
+    }
+
+    result, err := LogfmtFormatter.Format(record)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(string(result)).To(gm.Equal(
+        `ts=2019-03-09T14:59:50Z level=info msg=Foo ` +
+            `caller="foo/bar.go:12 foo.Bar" fields.caller=clash`,
+    ))
+}
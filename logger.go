@@ -1,192 +1,210 @@
 package logging
 
 import (
-    "encoding/json"
+    "context"
+    stderrors "errors"
     "fmt"
     "io"
-    "log"
-    "regexp"
-    "strings"
+    "log/slog"
+    "os"
+    "sync"
+    "sync/atomic"
     "time"
 
     "github.com/pkg/errors"
 )
 
-var keySantizationRegexp = regexp.MustCompile(`[\n\r\s]`)
+// exitFunc is called by Fatal; it is a variable so it can be replaced in
+// tests.
+var exitFunc = os.Exit
 
 // Logger is a logger instance that provides a unified interface for logging
 // data.
+//
+// A Logger is safe for concurrent use. Its configuration is held in an
+// immutable snapshot which is swapped out atomically whenever it changes so
+// logging never has to wait on a lock.
 type Logger struct {
     identifier string
-    writerLoggers map[io.Writer]*log.Logger
-    logLevelsEnabled map[LogLevel]bool
-    format LogFormat
-    extraGenerators []ExtrasGenerator
+    // holder is shared between a Logger and any children made from it with
+    // With so they all see the same configuration.
+    holder *stateHolder
+    // fields are the fields bound to this Logger with With; nil for loggers
+    // which aren't children.
+    fields *boundFields
 }
 
-// Used for standard formats so you don't get super weird logs. All bets are
-// off for JSON though.
-func sanitizeKey(key string) string {
-    return keySantizationRegexp.ReplaceAllString(strings.TrimSpace(key), "_")
+// stateHolder holds the current loggerState for a Logger.
+type stateHolder struct {
+    // mutex serialises changes to the state; readers never take it.
+    mutex sync.Mutex
+    state atomic.Value
 }
 
-func padStringRight(str, pad string, count int) string {
-    result := str
-    for i := 0; i < count; i++ {
-        result += pad
-    }
-
-    return result
+// loggerState is a snapshot of a Logger's configuration. Once a loggerState
+// has been stored in a Logger it must never be modified; changes are made to a
+// copy which then replaces it.
+type loggerState struct {
+    // sinks holds each writer along with its configuration.
+    sinks map[io.Writer]*sink
+    // minSeverity is the severity of the level the logger is set to; only
+    // logs with at least this severity are written.
+    minSeverity int
+    formatter Formatter
+    extraGenerators []ExtrasGenerator
+    contextExtractors []ContextExtractor
+    fieldOrder FieldOrder
+    timestampFormat TimestampFormat
+    utc bool
+    // clock is nil when the global clock should be used.
+    clock Clock
+    fieldNames FieldNames
+    // caller enables capturing the location each log was made from.
+    caller bool
+    // callerSkip is the number of extra frames to skip when capturing the
+    // caller for loggers which are wrapped by other helpers.
+    callerSkip int
+    // ownedWriters are closed when the logger is closed.
+    ownedWriters []io.Closer
+    // slogHandlers are passed every log as well as the sinks.
+    slogHandlers []slog.Handler
+    // sampler drops logs according to the sampling policy if there is one.
+    sampler *sampler
+    // rateLimiter drops logs over the rate limit if there is one.
+    rateLimiter *rateLimiter
+    // deduplicator collapses repeated logs if deduplication is enabled.
+    deduplicator *deduplicator
 }
 
-func formatStandardBody(bodyMap map[string]interface{}) []byte {
-    var (
-        firstParts [2]string
-        parts []string
-        lastPart string
-    )
-
-    for key, value := range bodyMap {
-        valueString := fmt.Sprint(value)
+// copy makes a shallow copy of the state. Maps and slices are shared so they
+// must be replaced rather than modified on the copy.
+func (self loggerState) copy() *loggerState {
+    return &self
+}
 
-        // TODO: Maybe this logic goes somewhere else?
-        if key == "message" {
-            lastPart = valueString
-        } else if key == "log_level" {
-            firstParts[1] = valueString
-        } else if key == "timestamp" {
-            firstParts[0] = valueString
-        } else {
-            partString := fmt.Sprintf(
-                `%s="%s"`, sanitizeKey(key), valueString,
-            )
-            parts = append(parts, partString)
-        }
+func newLoggerWithState(identifier string, state *loggerState) *Logger {
+    logger := &Logger{
+        identifier: identifier,
+        holder: new(stateHolder),
     }
+    // NOTE: The state may be another logger's so it's resolved on a copy
+    //       rather than modified.
+    state = state.copy()
+    state.resolveWriterFormatters()
+    logger.holder.state.Store(state)
 
-    parts = append(firstParts[:], append(parts, lastPart)...)
-    logLine := strings.Join(parts, " ")
-    return []byte(logLine)
+    return logger
 }
 
-func formatStandardBodyExtended(bodyMap map[string]interface{}) []byte {
-    var (
-        keys,
-        values []string
-        firstKeys,
-        firstValues [2]string
-        lastKeys,
-        lastValues [1]string
-    )
-
-    for key, value := range bodyMap {
-        valueString := fmt.Sprint(value)
-
-        keyLen := len(key)
-        valueLen := len(valueString)
-
-        finalKey := sanitizeKey(key)
-        finalValue := valueString
-        if keyLen < valueLen {
-            finalKey = padStringRight(key, " ", valueLen - keyLen)
-        } else if keyLen > valueLen {
-            finalValue = padStringRight(valueString, " ", keyLen - valueLen)
-        }
-
-        // TODO: Maybe this logic goes somewhere else?
-        if key == "message" {
-            lastKeys[0] = finalKey
-            lastValues[0] = finalValue
-        } else if key == "log_level" {
-            firstKeys[1] = finalKey
-            firstValues[1] = finalValue
-        } else if key == "timestamp" {
-            firstKeys[0] = finalKey
-            firstValues[0] = finalValue
-        } else {
-            keys = append(keys, finalKey)
-            values = append(values, finalValue)
-        }
-    }
-
-    keys = append(firstKeys[:], keys...)
-    values = append(firstValues[:], values...)
+func (self *Logger) loadState() *loggerState {
+    return self.holder.state.Load().(*loggerState)
+}
 
-    headerString := strings.Join(keys, " | ")
-    valuesString := strings.Join(values, " | ")
+// updateState applies update to a copy of the current state and then
+// atomically replaces the current state with it.
+func (self *Logger) updateState(update func(*loggerState)) {
+    self.holder.mutex.Lock()
+    defer self.holder.mutex.Unlock()
 
-    return []byte(headerString + "\n" + valuesString)
+    newState := self.loadState().copy()
+    update(newState)
+    newState.resolveWriterFormatters()
+    self.holder.state.Store(newState)
 }
 
-func (self Logger) clone() *Logger {
-    newWriterLoggers := make(map[io.Writer]*log.Logger)
-    for w, l := range self.writerLoggers {
-        newWriterLoggers[w] = l
-    }
-    newLogsEnabled := make(map[LogLevel]bool)
-    for logLevel, _ := range self.logLevelsEnabled {
-        newLogsEnabled[logLevel] = true
-    }
-    return &Logger{
-        identifier: "",
-        writerLoggers: newWriterLoggers,
-        logLevelsEnabled: newLogsEnabled,
-        format: self.format,
-        extraGenerators: self.extraGenerators,
-    }
-}
+func (self *Logger) clone() *Logger {
+    // NOTE: The state is immutable so it can be shared safely.
+    logger := newLoggerWithState("", self.loadState())
+    logger.fields = self.fields
 
+    return logger
+}
 
-func (self Logger) applyExtras(extras []Extras) map[string]interface{} {
-    allExtras := make(map[string]interface{})
+func (self *Logger) applyExtras(extras []Extras) []Field {
+    fields := self.fields.collect()
     for _, extra := range extras {
-        for key, value := range extra {
-            allExtras[key] = value
-        }
+        fields = appendExtras(fields, extra)
     }
 
-    return allExtras
+    return fields
 }
 
-func (self Logger) formatMessage(
-    logBody map[string]interface{},
-) []byte {
-    switch self.format {
-    case JSON:
-        bodyBytes, err := json.Marshal(logBody)
+func formatMessage(formatter Formatter, record *Record) []byte {
+    if formatter != nil {
+        bodyBytes, err := formatter.Format(record)
         if err == nil {
             return bodyBytes
         }
-    case Standard:
-        return formatStandardBody(logBody)
-    case StandardExtended:
-        return formatStandardBodyExtended(logBody)
     }
 
     // NOTE: Fallback on straight message if we have a problem with marshaling.
     // This is very unlikely to happen.
     return []byte(fmt.Sprintf(
         "Error while marshalling log with message '%s'.",
-        logBody["message"],
+        record.Message,
     ))
 }
 
-func (self Logger) addDefaults(
-    logLevel LogLevel, message string, logBody map[string]interface{},
-) map[string]interface{} {
-    logBody["message"] = message
-    logBody["log_level"] = logLevel
-    logBody["timestamp"] = timestamp{time.Now()}
+func (self *loggerState) now() time.Time {
+    if self.clock != nil {
+        return self.clock.Now()
+    }
 
-    return logBody
+    return GetGlobalClock().Now()
 }
 
-func (self Logger) levelEnabled(level LogLevel) bool {
-    _, ok := self.logLevelsEnabled[level]
-    return ok
+func (self *Logger) newRecord(
+    state *loggerState, logLevel LogLevel, message string, fields []Field,
+) *Record {
+    if state.fieldOrder == SortedOrder {
+        sortFields(fields)
+    }
+
+    now := state.now()
+    if state.utc {
+        now = now.UTC()
+    }
+
+    return &Record{
+        Time: now,
+        Level: logLevel,
+        Message: message,
+        LoggerIdentifier: self.identifier,
+        Fields: fields,
+        TimestampFormat: state.timestampFormat,
+        FieldNames: state.fieldNames.merge(GetGlobalFieldNames()),
+    }
+}
+
+// levelEnabled is true if any of the sinks or slog Handlers will write logs
+// at the level.
+func (self *loggerState) levelEnabled(level LogLevel) bool {
+    severity, ok := level.Severity()
+    return ok && self.severityEnabled(severity)
 }
 
-func (self Logger) applyInstanceExtras() ([]Extras, error) {
+// severityEnabled is true if any of the sinks or slog Handlers will write
+// logs with the severity.
+func (self *loggerState) severityEnabled(severity int) bool {
+    for _, writerSink := range self.sinks {
+        if self.sinkEnabled(writerSink, severity) {
+            return true
+        }
+    }
+
+    return len(self.slogHandlers) != 0 && severity >= self.minSeverity
+}
+
+// sinkEnabled is true if the sink writes logs with the severity.
+func (self *loggerState) sinkEnabled(writerSink *sink, severity int) bool {
+    if writerSink.minSeverity != nil {
+        return severity >= *writerSink.minSeverity
+    }
+
+    return severity >= self.minSeverity
+}
+
+func (self *loggerState) applyInstanceExtras() ([]Extras, error) {
     var allExtras []Extras
     for i, extraFunc := range self.extraGenerators {
         newExtras, err := extraFunc()
@@ -201,9 +219,9 @@ func (self Logger) applyInstanceExtras() ([]Extras, error) {
     return allExtras, nil
 }
 
-func (self Logger) applyGlobalExtras() ([]Extras, error) {
+func (self *Logger) applyGlobalExtras() ([]Extras, error) {
     var allExtras []Extras
-    for i, extraFunc := range globalExtraGenerators {
+    for i, extraFunc := range GetGlobalExtras() {
         newExtras, err := extraFunc()
         if err != nil {
             return nil, errors.Wrapf(
@@ -216,27 +234,222 @@ func (self Logger) applyGlobalExtras() ([]Extras, error) {
     return allExtras, nil
 }
 
-func (self Logger) internalException(err error, message string) {
-    extras := Extras{
-        "error":  fmt.Sprintf("%+v", errors.WithStack(err)),
+func (self *Logger) internalException(
+    state *loggerState, err error, message string,
+) {
+    fields := []Field{
+        {Key: "error", Value: newErrorField(err, 0)},
     }
 
-    body := self.addDefaults(ERROR, message, extras)
+    self.writeRecord(
+        nil, state, self.newRecord(state, ERROR, message, fields),
+    )
+}
 
-    logline := self.formatMessage(body)
+// writeRecord formats the provided Record and writes it to each of the sinks
+// in state which accept it. It's also passed to any slog Handlers with ctx
+// (if it isn't nil).
+func (self *Logger) writeRecord(
+    ctx context.Context, state *loggerState, record *Record,
+) {
+    severity, ok := record.Level.Severity()
+    if !ok {
+        return
+    }
 
-    self.Log(ERROR, logline)
+    if len(state.slogHandlers) != 0 && severity >= state.minSeverity {
+        state.handleSlog(ctx, record, severity)
+    }
+
+    // NOTE: The output of the logger's formatter is shared between every
+    //       sink using it as long as it isn't formatting per writer.
+    var defaultBytes []byte
+    for _, writerSink := range state.sinks {
+        if !state.sinkEnabled(writerSink, severity) {
+            continue
+        }
+        if writerSink.filter != nil && !writerSink.filter(record) {
+            continue
+        }
+
+        formatter := writerSink.formatter
+        if formatter == nil {
+            formatter = state.formatter
+        }
+        if writerSink.writerFormatter != nil {
+            formatter = writerSink.writerFormatter
+        } else if writerSink.formatter == nil {
+            if defaultBytes == nil {
+                defaultBytes = formatMessage(formatter, record)
+            }
+            writerSink.logger.Printf("%s", defaultBytes)
+            continue
+        }
+
+        writerSink.logger.Printf("%s", formatMessage(formatter, record))
+    }
 }
 
-func (self Logger) logToLevel(
-    level LogLevel, message string, extras []Extras,
+// logToLevel logs the message at the provided level. skip is the number of
+// frames between the function calling logToLevel and the code that made the
+// log (0 for the public Logger methods).
+func (self *Logger) logToLevel(
+    skip int, level LogLevel, message string, extras []Extras,
 ) {
+    self.logToLevelContext(nil, skip + 1, level, message, extras)
+}
+
+// logToLevelContext logs the message running any context extractors with ctx
+// unless it is nil. skip is the same as for logToLevel.
+func (self *Logger) logToLevelContext(
+    ctx context.Context,
+    skip int,
+    level LogLevel,
+    message string,
+    extras []Extras,
+) {
+    // NOTE: A single snapshot is used for the whole log so that concurrent
+    //       configuration changes can't result in a half-applied config.
+    state := self.loadState()
+    if !state.levelEnabled(level) ||
+        self.suppressed(state, level, message, extras) {
+        return
+    }
+
+    self.logEnabled(ctx, skip + 1, state, level, message, extras)
+}
+
+// logEnabled logs the message which has already been checked to be enabled
+// and not suppressed. skip is the same as for logToLevel.
+func (self *Logger) logEnabled(
+    ctx context.Context,
+    skip int,
+    state *loggerState,
+    level LogLevel,
+    message string,
+    extras []Extras,
+) {
+    var caller *Caller
+    if state.caller {
+        // NOTE: Skip this function and the one calling it.
+        caller = newCaller(skip + state.callerSkip + 2)
+    }
+
+    self.logRecord(ctx, state, level, message, extras, caller)
+}
+
+// suppressed decides whether a log is dropped by deduplication, sampling or
+// rate limiting (in that order) so it can be skipped before any extras are
+// generated or it's formatted. Reports for logs dropped previously are written
+// when they're due.
+func (self *Logger) suppressed(
+    state *loggerState, level LogLevel, message string, extras []Extras,
+) bool {
+    exempt := level == FATAL || level == PANIC
+
+    if state.deduplicator != nil {
+        if exempt {
+            self.writeDedupReport(state, state.deduplicator.drain())
+        } else {
+            allowed, report := state.deduplicator.check(&dedupRecord{
+                logger: self,
+                level: level,
+                message: message,
+                extras: extras,
+            })
+            self.writeDedupReport(state, report)
+            if !allowed {
+                return true
+            }
+        }
+    }
+
+    if state.sampler != nil {
+        allowed, reports := state.sampler.sample(
+            self, level, message, state.now(),
+        )
+        self.writeSampleReports(state, reports)
+        if !allowed {
+            return true
+        }
+    }
+
+    if state.rateLimiter != nil && !exempt {
+        allowed, report := state.rateLimiter.allow(level, state.now())
+        self.writeRateLimitReport(state, report)
+        if !allowed {
+            return true
+        }
+    }
+
+    return false
+}
+
+// reportDropped writes reports for any logs which have been dropped by
+// deduplication, sampling or rate limiting since they were last reported.
+func (self *Logger) reportDropped(state *loggerState) {
+    if state.deduplicator != nil {
+        self.writeDedupReport(state, state.deduplicator.drain())
+    }
+    if state.sampler != nil {
+        self.writeSampleReports(state, state.sampler.drain())
+    }
+    if state.rateLimiter != nil {
+        self.writeRateLimitReport(state, state.rateLimiter.drain())
+    }
+}
+
+// logRecord runs all the extras for a log which has already been checked to
+// be enabled and writes the resulting Record.
+func (self *Logger) logRecord(
+    ctx context.Context,
+    state *loggerState,
+    level LogLevel,
+    message string,
+    extras []Extras,
+    caller *Caller,
+) {
+    record := self.buildRecord(ctx, state, level, message, extras, caller)
+    self.writeRecord(ctx, state, record)
+}
+
+// buildRecord runs all the extras for a log and makes its Record.
+func (self *Logger) buildRecord(
+    ctx context.Context,
+    state *loggerState,
+    level LogLevel,
+    message string,
+    extras []Extras,
+    caller *Caller,
+) *Record {
     allExtras := extras
-    extraGenerators, err := self.applyInstanceExtras()
+    if ctx != nil {
+        contextExtras, err := runContextExtractors(
+            ctx, state.contextExtractors,
+        )
+        allExtras = append(allExtras, contextExtras...)
+        if err != nil {
+            self.internalException(
+                state, err, "Error while running logger context extractors.",
+            )
+        }
+
+        globalContextExtras, err := runContextExtractors(
+            ctx, GetGlobalContextExtractors(),
+        )
+        allExtras = append(allExtras, globalContextExtras...)
+        if err != nil {
+            self.internalException(
+                state, err, "Error while running global context extractors.",
+            )
+        }
+    }
+
+    extraGenerators, err := state.applyInstanceExtras()
     allExtras = append(allExtras, extraGenerators...)
     if err != nil {
         self.internalException(
-            err, "Error while running logger instance extras.",
+            state, err, "Error while running logger instance extras.",
         )
     }
 
@@ -244,59 +457,274 @@ func (self Logger) logToLevel(
     allExtras = append(allExtras, globalExtras...)
     if err != nil {
         self.internalException(
-            err, "Error while running global logger extras.",
+            state, err, "Error while running global logger extras.",
         )
     }
 
-    extrasMap := self.applyExtras(allExtras)
-    extrasMap = self.addDefaults(level, message, extrasMap)
-
-    logBody := self.formatMessage(extrasMap)
+    record := self.newRecord(
+        state, level, message, self.applyExtras(allExtras),
+    )
+    record.Caller = caller
 
-    self.Log(level, logBody)
+    return record
 }
 
-// Log is the most basic log function. It logs the bytes directly if the
-// loglevel is enabled. No aditional formating is done.
-func (self Logger) Log(level LogLevel, messageBytes []byte) {
-    if !self.levelEnabled(level) {
+func (self *loggerState) log(level LogLevel, messageBytes []byte) {
+    severity, ok := level.Severity()
+    if !ok {
         return
     }
 
-    for _, logger := range self.writerLoggers {
-        logger.Printf("%s", messageBytes)
+    for _, writerSink := range self.sinks {
+        if self.sinkEnabled(writerSink, severity) {
+            writerSink.logger.Printf("%s", messageBytes)
+        }
+    }
+}
+
+// flushWriters flushes any writers which buffer their output.
+func (self *loggerState) flushWriters() error {
+    var errs []error
+    for writer := range self.sinks {
+        if err := flushWriter(writer); err != nil {
+            errs = append(errs, err)
+        }
+    }
+
+    return stderrors.Join(errs...)
+}
+
+// flushWriter flushes the writer if it buffers its output.
+func flushWriter(writer io.Writer) error {
+    switch flusher := writer.(type) {
+    case *os.File:
+        // NOTE: Files aren't buffered and Sync fails for terminals & pipes
+        //       (like stdout usually is) so the result is ignored.
+        _ = flusher.Sync()
+    case interface{ Flush() error }:
+        return flusher.Flush()
+    case interface{ Sync() error }:
+        return flusher.Sync()
+    }
+
+    return nil
+}
+
+// closeWriters closes each of the writers.
+func closeWriters(writers []io.Closer) error {
+    var errs []error
+    for _, writer := range writers {
+        if err := writer.Close(); err != nil {
+            errs = append(errs, err)
+        }
     }
+
+    return stderrors.Join(errs...)
+}
+
+// Log is the most basic log function. It logs the bytes directly if the
+// loglevel is enabled. No aditional formating is done.
+func (self *Logger) Log(level LogLevel, messageBytes []byte) {
+    self.loadState().log(level, messageBytes)
+}
+
+// Trace logs according to this loggers formatter at the TRACE level.
+func (self *Logger) Trace(message string, extras ...Extras) {
+    self.logToLevel(0, TRACE, message, extras)
 }
 
 // Debug logs according to this loggers formatter at the DEBUG level.
-func (self Logger) Debug(message string, extras ...Extras) {
-    self.logToLevel(DEBUG, message, extras)
+func (self *Logger) Debug(message string, extras ...Extras) {
+    self.logToLevel(0, DEBUG, message, extras)
 }
 
 // Info logs according to this loggers formatter at the INFO level.
-func (self Logger) Info(message string, extras ...Extras) {
-    self.logToLevel(INFO, message, extras)
+func (self *Logger) Info(message string, extras ...Extras) {
+    self.logToLevel(0, INFO, message, extras)
 }
 
 // Warn logs according to this loggers formatter at the WARN level.
-func (self Logger) Warn(message string, extras ...Extras) {
-    self.logToLevel(WARN, message, extras)
+func (self *Logger) Warn(message string, extras ...Extras) {
+    self.logToLevel(0, WARN, message, extras)
 }
 
 // Error logs according to this loggers formatter at the ERROR level.
-func (self Logger) Error(message string, extras ...Extras) {
-    self.logToLevel(ERROR, message, extras)
+func (self *Logger) Error(message string, extras ...Extras) {
+    self.logToLevel(0, ERROR, message, extras)
+}
+
+// Tracef logs a message formatted with fmt.Sprintf at the TRACE level.
+func (self *Logger) Tracef(format string, args ...interface{}) {
+    self.logToLevel(0, TRACE, fmt.Sprintf(format, args...), nil)
+}
+
+// Tracew logs a message at the TRACE level with extras provided as
+// alternating keys and values:
+//   logger.Tracew("Message", "key1", value1, "key2", value2)
+func (self *Logger) Tracew(message string, keysAndValues ...interface{}) {
+    self.logToLevel(0, TRACE, message, extrasFromKeysAndValues(keysAndValues))
 }
 
-// Exception logs an error's contents & stack at an error level.
-func (self Logger) Exception(
+// Debugf logs a message formatted with fmt.Sprintf at the DEBUG level.
+func (self *Logger) Debugf(format string, args ...interface{}) {
+    self.logToLevel(0, DEBUG, fmt.Sprintf(format, args...), nil)
+}
+
+// Debugw logs a message at the DEBUG level with extras provided as
+// alternating keys and values:
+//   logger.Debugw("Message", "key1", value1, "key2", value2)
+func (self *Logger) Debugw(message string, keysAndValues ...interface{}) {
+    self.logToLevel(0, DEBUG, message, extrasFromKeysAndValues(keysAndValues))
+}
+
+// Infof logs a message formatted with fmt.Sprintf at the INFO level.
+func (self *Logger) Infof(format string, args ...interface{}) {
+    self.logToLevel(0, INFO, fmt.Sprintf(format, args...), nil)
+}
+
+// Infow logs a message at the INFO level with extras provided as
+// alternating keys and values:
+//   logger.Infow("Message", "key1", value1, "key2", value2)
+func (self *Logger) Infow(message string, keysAndValues ...interface{}) {
+    self.logToLevel(0, INFO, message, extrasFromKeysAndValues(keysAndValues))
+}
+
+// Warnf logs a message formatted with fmt.Sprintf at the WARN level.
+func (self *Logger) Warnf(format string, args ...interface{}) {
+    self.logToLevel(0, WARN, fmt.Sprintf(format, args...), nil)
+}
+
+// Warnw logs a message at the WARN level with extras provided as
+// alternating keys and values:
+//   logger.Warnw("Message", "key1", value1, "key2", value2)
+func (self *Logger) Warnw(message string, keysAndValues ...interface{}) {
+    self.logToLevel(0, WARN, message, extrasFromKeysAndValues(keysAndValues))
+}
+
+// Errorf logs a message formatted with fmt.Sprintf at the ERROR level.
+func (self *Logger) Errorf(format string, args ...interface{}) {
+    self.logToLevel(0, ERROR, fmt.Sprintf(format, args...), nil)
+}
+
+// Errorw logs a message at the ERROR level with extras provided as
+// alternating keys and values:
+//   logger.Errorw("Message", "key1", value1, "key2", value2)
+func (self *Logger) Errorw(message string, keysAndValues ...interface{}) {
+    self.logToLevel(0, ERROR, message, extrasFromKeysAndValues(keysAndValues))
+}
+
+// TraceContext logs according to this loggers formatter at the TRACE level
+// including any extras from this logger's context extractors for ctx.
+func (self *Logger) TraceContext(
+    ctx context.Context, message string, extras ...Extras,
+) {
+    self.logToLevelContext(ctx, 0, TRACE, message, extras)
+}
+
+// DebugContext logs according to this loggers formatter at the DEBUG level
+// including any extras from this logger's context extractors for ctx.
+func (self *Logger) DebugContext(
+    ctx context.Context, message string, extras ...Extras,
+) {
+    self.logToLevelContext(ctx, 0, DEBUG, message, extras)
+}
+
+// InfoContext logs according to this loggers formatter at the INFO level
+// including any extras from this logger's context extractors for ctx.
+func (self *Logger) InfoContext(
+    ctx context.Context, message string, extras ...Extras,
+) {
+    self.logToLevelContext(ctx, 0, INFO, message, extras)
+}
+
+// WarnContext logs according to this loggers formatter at the WARN level
+// including any extras from this logger's context extractors for ctx.
+func (self *Logger) WarnContext(
+    ctx context.Context, message string, extras ...Extras,
+) {
+    self.logToLevelContext(ctx, 0, WARN, message, extras)
+}
+
+// ErrorContext logs according to this loggers formatter at the ERROR level
+// including any extras from this logger's context extractors for ctx.
+func (self *Logger) ErrorContext(
+    ctx context.Context, message string, extras ...Extras,
+) {
+    self.logToLevelContext(ctx, 0, ERROR, message, extras)
+}
+
+// LogAt logs according to this loggers formatter at the provided level which
+// can be any built-in or registered LogLevel.
+func (self *Logger) LogAt(level LogLevel, message string, extras ...Extras) {
+    if _, ok := level.Severity(); !ok {
+        state := self.loadState()
+        self.internalException(
+            state,
+            errors.Errorf("Incorrect LogLevel: '%s'", level),
+            "Error while logging at unknown level.",
+        )
+        return
+    }
+
+    self.logToLevel(0, level, message, extras)
+}
+
+// Fatal logs according to this loggers formatter at the FATAL level, flushes
+// this logger's writers and then exits the program with a status of 1.
+func (self *Logger) Fatal(message string, extras ...Extras) {
+    self.fatal(0, message, extras)
+}
+
+func (self *Logger) fatal(skip int, message string, extras []Extras) {
+    self.logToLevel(skip + 1, FATAL, message, extras)
+    _ = self.Flush()
+    exitFunc(1)
+}
+
+// Panic logs according to this loggers formatter at the PANIC level and then
+// panics with the message.
+func (self *Logger) Panic(message string, extras ...Extras) {
+    self.logToLevel(0, PANIC, message, extras)
+    panic(message)
+}
+
+// Exception logs an error at an error level in an "error" field. For JSON the
+// error is output as an object with its message, type, chain of causes and
+// the stack it originated from (or where it was logged if it has none).
+func (self *Logger) Exception(
     err error, message string, extras ...Extras,
 ) {
+    self.exception(nil, 0, err, message, extras)
+}
+
+// ExceptionContext logs an error the same way as Exception including any
+// extras from this logger's context extractors for ctx.
+func (self *Logger) ExceptionContext(
+    ctx context.Context, err error, message string, extras ...Extras,
+) {
+    self.exception(ctx, 0, err, message, extras)
+}
+
+func (self *Logger) exception(
+    ctx context.Context, skip int, err error, message string, extras []Extras,
+) {
+    // NOTE: The error itself stands in for its field while checking whether
+    //       the log is suppressed so the stack is only captured for logs
+    //       which will be written. The extras are sliced to their length so
+    //       appending never writes to the caller's backing array.
+    extras = extras[:len(extras):len(extras)]
+    state := self.loadState()
+    if !state.levelEnabled(ERROR) || self.suppressed(
+        state, ERROR, message, append(extras, Extras{"error": err}),
+    ) {
+        return
+    }
+
     extrasWithErr := append(extras, Extras{
-        "error":  fmt.Sprintf("%+v", errors.WithStack(err)),
+        "error": newErrorField(err, skip + 2),
     })
 
-    self.logToLevel(ERROR, message, extrasWithErr)
+    self.logEnabled(ctx, skip + 1, state, ERROR, message, extrasWithErr)
 }
 
 // AddDefaultExtras adds extra(s) which will be added for every log made with
@@ -305,7 +733,11 @@ func (self *Logger) AddDefaultExtras(
     extras ExtrasGenerator, otherExtras ...ExtrasGenerator,
 ) {
     allExtras := append([]ExtrasGenerator{extras}, otherExtras...)
-    self.extraGenerators = append(self.extraGenerators, allExtras...)
+    self.updateState(func(state *loggerState) {
+        state.extraGenerators = appendCopy(
+            state.extraGenerators, allExtras,
+        )
+    })
 }
 
 // SetDefaultExtras sets (overriding) the extra(s) which will be added for
@@ -314,63 +746,266 @@ func (self *Logger) SetDefaultExtras(
     extras ExtrasGenerator, otherExtras ...ExtrasGenerator,
 ) {
     allExtras := append([]ExtrasGenerator{extras}, otherExtras...)
-    self.extraGenerators = allExtras
+    self.updateState(func(state *loggerState) {
+        state.extraGenerators = allExtras
+    })
+}
+
+// AddContextExtractors adds extractor(s) which will be run for every log
+// made with a context by this logger.
+func (self *Logger) AddContextExtractors(
+    extractor ContextExtractor, extractors ...ContextExtractor,
+) {
+    allExtractors := append([]ContextExtractor{extractor}, extractors...)
+    self.updateState(func(state *loggerState) {
+        state.contextExtractors = appendCopy(
+            state.contextExtractors, allExtractors,
+        )
+    })
 }
 
 // SetFormat changes the loggers format to the provided format.
 func (self *Logger) SetFormat(logFormat LogFormat) {
-    self.format = logFormat
+    self.SetFormatter(GetFormatter(logFormat))
+}
+
+// SetFormatter changes the loggers formatter to the provided Formatter.
+func (self *Logger) SetFormatter(formatter Formatter) {
+    self.updateState(func(state *loggerState) {
+        state.formatter = formatter
+    })
+}
+
+// SetFieldOrder changes the order this logger will output fields in.
+func (self *Logger) SetFieldOrder(fieldOrder FieldOrder) {
+    self.updateState(func(state *loggerState) {
+        state.fieldOrder = fieldOrder
+    })
+}
+
+// SetTimestampFormat changes how this logger outputs timestamps.
+func (self *Logger) SetTimestampFormat(timestampFormat TimestampFormat) {
+    self.updateState(func(state *loggerState) {
+        state.timestampFormat = timestampFormat
+    })
+}
+
+// SetUTC toggles whether this logger outputs timestamps in UTC rather than
+// local time.
+func (self *Logger) SetUTC(utc bool) {
+    self.updateState(func(state *loggerState) {
+        state.utc = utc
+    })
+}
+
+// SetClock sets the Clock this logger gets the time from. Passing nil will
+// make the logger use the global Clock.
+func (self *Logger) SetClock(clock Clock) {
+    self.updateState(func(state *loggerState) {
+        state.clock = clock
+    })
+}
+
+// SetCaller toggles whether this logger captures the file, line & function
+// each log was made from.
+func (self *Logger) SetCaller(enabled bool) {
+    self.updateState(func(state *loggerState) {
+        state.caller = enabled
+    })
+}
+
+// SetCallerSkip sets the number of extra frames this logger skips when
+// capturing the caller. This is useful when the logger is only ever called
+// through a helper function so that the helper's caller is logged instead.
+func (self *Logger) SetCallerSkip(skip int) {
+    self.updateState(func(state *loggerState) {
+        state.callerSkip = skip
+    })
+}
+
+// SetFieldNames changes the keys this logger uses for the reserved parts of a
+// log.
+func (self *Logger) SetFieldNames(fieldNames FieldNames) {
+    self.updateState(func(state *loggerState) {
+        state.fieldNames = fieldNames
+    })
 }
 
 // SetWriters sets the internal logger's writers to the provided writer(s).
+// The writers use this logger's level and format; any sink configuration
+// they had is removed.
 func (self *Logger) SetWriters(w io.Writer, otherWs ...io.Writer) {
-    newWriters := make(map[io.Writer]*log.Logger)
-    for _, writer := range append([]io.Writer{w}, otherWs...) {
-        newWriters[writer] = log.New(writer, "", 0)
-    }
-
-    self.writerLoggers = newWriters
+    self.updateState(func(state *loggerState) {
+        state.sinks = addSinks(
+            nil, append([]io.Writer{w}, otherWs...), state.sinks,
+        )
+    })
 }
 
 // AddWriters adds writers provided to the existing writers if they don't
-// already exist (duplicates will not be added multiple times).
+// already exist (duplicates will not be added multiple times). New writers
+// use this logger's level and format.
 func (self *Logger) AddWriters(w io.Writer, otherWs ...io.Writer) {
-    for _, writer := range append([]io.Writer{w}, otherWs...) {
-        self.writerLoggers[writer] = log.New(writer, "", 0)
+    self.updateState(func(state *loggerState) {
+        state.sinks = addSinks(
+            state.sinks, append([]io.Writer{w}, otherWs...), state.sinks,
+        )
+    })
+}
+
+// AddSink adds the writer (replacing its configuration if it already exists)
+// with its own level, format and any other SinkOptions. An UnsetLogLevel or
+// UnsetFormat means this logger's level or format is used for the writer.
+func (self *Logger) AddSink(
+    writer io.Writer,
+    logLevel LogLevel,
+    logFormat LogFormat,
+    options ...SinkOption,
+) error {
+    var err error
+    self.updateState(func(state *loggerState) {
+        var writerSink *sink
+        writerSink, err = newSink(
+            writer,
+            existingLogger(writer, state.sinks),
+            logLevel,
+            logFormat,
+            options,
+        )
+        if err != nil {
+            return
+        }
+
+        // NOTE: The map is copied since the current one may be in use.
+        state.sinks = addSinks(state.sinks, nil, nil)
+        state.sinks[writer] = writerSink
+    })
+    if err != nil {
+        return errors.Wrap(err, "Error while adding sink")
     }
+
+    return nil
+}
+
+// AddOwnedWriters adds writers in the same way as AddWriters but the logger
+// takes ownership of them so they're closed when the logger is closed.
+func (self *Logger) AddOwnedWriters(
+    w io.WriteCloser, otherWs ...io.WriteCloser,
+) {
+    allWriters := append([]io.WriteCloser{w}, otherWs...)
+    self.updateState(func(state *loggerState) {
+        writers := make([]io.Writer, 0, len(allWriters))
+        closers := make([]io.Closer, 0, len(allWriters))
+        for _, writer := range allWriters {
+            writers = append(writers, writer)
+            closers = append(closers, writer)
+        }
+
+        state.sinks = addSinks(state.sinks, writers, state.sinks)
+        state.ownedWriters = appendCopy(state.ownedWriters, closers)
+    })
 }
 
 // RemoveWriter removes the provided writer if it is found.
 func (self *Logger) RemoveWriter(w io.Writer) {
-    if _, ok := self.writerLoggers[w]; ok {
-        delete(self.writerLoggers, w)
-    }
+    self.updateState(func(state *loggerState) {
+        if _, ok := state.sinks[w]; !ok {
+            return
+        }
+
+        newSinks := make(map[io.Writer]*sink)
+        for writer, writerSink := range state.sinks {
+            if writer != w {
+                newSinks[writer] = writerSink
+            }
+        }
+        state.sinks = newSinks
+    })
 }
 
 // SetLogLevel sets this logger to log at the provided LogLevel and below.
 func (self *Logger) SetLogLevel(logLevel LogLevel) error {
-    logsEnabled, err := logsEnabledFromLevel(logLevel)
+    minSeverity, err := severityForLevel(logLevel)
     if err != nil {
         return errors.Wrap(err, "Error while setting log level")
     }
 
-    self.logLevelsEnabled = logsEnabled
+    self.updateState(func(state *loggerState) {
+        state.minSeverity = minSeverity
+    })
 
     return nil
 }
 
 // Identifier provides this Logger's identifier in the global Logger registry.
-func (self Logger) Identifier() string {
+func (self *Logger) Identifier() string {
     return self.identifier
 }
 
-// Close removes this logger from the global Logger registry and performs any
-// cleanup tasks.
+// Flush flushes any of this logger's writers which buffer their output (such
+// as an AsyncWriter) so that everything logged so far has been written.
+func (self *Logger) Flush() error {
+    state := self.loadState()
+    self.reportDropped(state)
+
+    return state.flushWriters()
+}
+
+// Close removes this logger from the global Logger registry, flushes its
+// writers and closes any writers it owns (see WithOwnedWriters).
 // It is not required to call this function when you're done with a logger but
-// it is highly recommended to clear up memory and prevent accidental
-// identifier clashing.
-func (self Logger) Close() {
-    removeLogger(self.identifier)
+// it is highly recommended to clear up memory, prevent accidental identifier
+// clashing and make sure all logs have been written.
+//
+// Closing a child logger made with With or a logger which has already been
+// closed does nothing.
+func (self *Logger) Close() error {
+    if !removeLogger(self) {
+        return nil
+    }
+
+    state := self.loadState()
+    self.reportDropped(state)
+    flushErr := state.flushWriters()
+    closeErr := closeWriters(state.ownedWriters)
+    if err := stderrors.Join(flushErr, closeErr); err != nil {
+        return errors.Wrap(err, "Error while closing logger")
+    }
+
+    return nil
+}
+
+// With makes a child logger which logs the provided extras with every log on
+// top of any extras bound to this logger. The child shares its writers,
+// level, format and all other configuration with this logger (changing them on
+// either will affect both) but it isn't added to the global Logger registry.
+//
+// Extras passed when logging take precedence over those bound with With.
+func (self *Logger) With(extras Extras) *Logger {
+    return self.child(appendExtras(nil, extras))
+}
+
+// WithFields is like With but takes the extras as alternating keys and
+// values, in the same way as Infow, which keeps them in the provided order:
+//   child := logger.WithFields("request_id", requestID, "user", userID)
+func (self *Logger) WithFields(keysAndValues ...interface{}) *Logger {
+    var fields []Field
+    for _, extras := range extrasFromKeysAndValues(keysAndValues) {
+        fields = appendExtras(fields, extras)
+    }
+
+    return self.child(fields)
+}
+
+func (self *Logger) child(fields []Field) *Logger {
+    return &Logger{
+        identifier: self.identifier,
+        holder: self.holder,
+        fields: &boundFields{
+            parent: self.fields,
+            fields: fields,
+        },
+    }
 }
 
 func newLogger(
@@ -398,24 +1033,83 @@ func newLogger(
 
     newLogger.identifier = identifier
 
-    writerLoggers := loggerConfig.writerLoggers
-    if len(writerLoggers) != 0 {
-        newLogger.writerLoggers = writerLoggers
-    }
+    newLogger.updateState(func(state *loggerState) {
+        if len(loggerConfig.sinks) != 0 {
+            state.sinks = loggerConfig.sinks
+        }
 
-    logsEnabled := loggerConfig.logsEnabled
-    if len(logsEnabled) != 0 {
-        newLogger.logLevelsEnabled = logsEnabled
-    }
+        if loggerConfig.minSeverity != nil {
+            state.minSeverity = *loggerConfig.minSeverity
+        }
 
-    format := loggerConfig.logFormat
-    if format != UnsetFormat {
-        newLogger.format = format
-    }
+        formatter := loggerConfig.formatter
+        if formatter != nil {
+            state.formatter = formatter
+        }
 
-    newLogger.extraGenerators = append(
-        newLogger.extraGenerators, loggerConfig.extraGenerators...,
-    )
+        if loggerConfig.fieldOrder != nil {
+            state.fieldOrder = *loggerConfig.fieldOrder
+        }
+
+        if loggerConfig.timestampFormat != nil {
+            state.timestampFormat = *loggerConfig.timestampFormat
+        }
+
+        if loggerConfig.utc != nil {
+            state.utc = *loggerConfig.utc
+        }
+
+        if loggerConfig.clock != nil {
+            state.clock = loggerConfig.clock
+        }
+
+        if loggerConfig.fieldNames != nil {
+            state.fieldNames = *loggerConfig.fieldNames
+        }
+
+        if loggerConfig.caller != nil {
+            state.caller = *loggerConfig.caller
+        }
+
+        if loggerConfig.callerSkip != nil {
+            state.callerSkip = *loggerConfig.callerSkip
+        }
+
+        if len(loggerConfig.slogHandlers) != 0 {
+            state.slogHandlers = loggerConfig.slogHandlers
+        }
+
+        // NOTE: Sampling, rate limiting & deduplication counts aren't shared
+        //       with the base logger, only its configuration.
+        if loggerConfig.samplingPolicy != nil {
+            state.sampler = newSampler(loggerConfig.samplingPolicy)
+        } else if state.sampler != nil {
+            state.sampler = newSampler(state.sampler.policy)
+        }
+        if loggerConfig.rateLimit != nil {
+            state.rateLimiter = newRateLimiter(*loggerConfig.rateLimit)
+        } else if state.rateLimiter != nil {
+            state.rateLimiter = newRateLimiter(state.rateLimiter.limit)
+        }
+        if loggerConfig.deduplicationTimeout != nil {
+            state.deduplicator = newDeduplicator(
+                *loggerConfig.deduplicationTimeout,
+            )
+        } else if state.deduplicator != nil {
+            state.deduplicator = newDeduplicator(state.deduplicator.timeout)
+        }
+
+        // NOTE: Ownership isn't inherited from the base logger so writers
+        //       are only ever closed by one logger.
+        state.ownedWriters = loggerConfig.ownedWriters
+
+        state.extraGenerators = appendCopy(
+            state.extraGenerators, loggerConfig.extraGenerators,
+        )
+        state.contextExtractors = appendCopy(
+            state.contextExtractors, loggerConfig.contextExtractors,
+        )
+    })
 
     err := addLogger(identifier, newLogger)
     if err != nil {
@@ -440,3 +1134,12 @@ func CloneLogger(
 ) (*Logger, error) {
     return newLogger(identifier, baseLogger, options)
 }
+
+// appendCopy appends items to a copy of base so the backing array of base is
+// never shared with the result.
+func appendCopy[T any](base, items []T) []T {
+    newItems := make([]T, 0, len(base) + len(items))
+    newItems = append(newItems, base...)
+
+    return append(newItems, items...)
+}
@@ -3,22 +3,36 @@ package logging
 import (
     "io"
     "log"
+    "log/slog"
+    "time"
 
     "github.com/pkg/errors"
 )
 
 type loggerConfig struct{
-    writerLoggers map[io.Writer]*log.Logger
-    logsEnabled map[LogLevel]bool
-    logFormat LogFormat
+    sinks map[io.Writer]*sink
+    minSeverity *int
+    formatter Formatter
     extraGenerators []ExtrasGenerator
+    contextExtractors []ContextExtractor
+    fieldOrder *FieldOrder
+    timestampFormat *TimestampFormat
+    utc *bool
+    clock Clock
+    fieldNames *FieldNames
+    caller *bool
+    callerSkip *int
+    ownedWriters []io.Closer
+    slogHandlers []slog.Handler
+    samplingPolicy *samplingPolicy
+    rateLimit *rateLimit
+    deduplicationTimeout *time.Duration
 }
 
 func newLoggerConfig() *loggerConfig {
     return &loggerConfig{
-        writerLoggers: make(map[io.Writer]*log.Logger),
-        logsEnabled: make(map[LogLevel]bool),
-        logFormat: UnsetFormat,
+        sinks: make(map[io.Writer]*sink),
+        formatter: nil,
         extraGenerators: make([]ExtrasGenerator, 0),
     }
 }
@@ -29,7 +43,7 @@ type LoggerOption func(*loggerConfig) error
 // WithLogLevel sets this Logger's log level to the provided LogLevel.
 func WithLogLevel(logLevel LogLevel) LoggerOption {
     return func(loggerConfig *loggerConfig) error {
-        logsEnabled, err := logsEnabledFromLevel(logLevel)
+        minSeverity, err := severityForLevel(logLevel)
         if err != nil {
             return errors.Wrapf(
                 err,
@@ -38,7 +52,7 @@ func WithLogLevel(logLevel LogLevel) LoggerOption {
             )
         }
 
-        loggerConfig.logsEnabled = logsEnabled
+        loggerConfig.minSeverity = &minSeverity
 
         return nil
     }
@@ -47,7 +61,31 @@ func WithLogLevel(logLevel LogLevel) LoggerOption {
 // WithFormat sets the new Logger's format to the provided format.
 func WithFormat(logFormat LogFormat) LoggerOption {
     return func(loggerConfig *loggerConfig) error {
-        loggerConfig.logFormat = logFormat
+        if logFormat == UnsetFormat {
+            return nil
+        }
+
+        formatter := GetFormatter(logFormat)
+        if formatter == nil {
+            return errors.Errorf("Unknown log format '%d'", logFormat)
+        }
+
+        loggerConfig.formatter = formatter
+
+        return nil
+    }
+}
+
+// WithFormatter sets the new Logger's formatter to the provided Formatter.
+// This is useful for one-off formatters that don't need to be registered
+// with RegisterFormat.
+func WithFormatter(formatter Formatter) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        if formatter == nil {
+            return errors.New("Formatter cannot be nil")
+        }
+
+        loggerConfig.formatter = formatter
 
         return nil
     }
@@ -57,7 +95,49 @@ func WithFormat(logFormat LogFormat) LoggerOption {
 func WithLogWriters(primary io.Writer, others ... io.Writer) LoggerOption {
     return func(loggerConfig *loggerConfig) error {
         for _, writer := range append([]io.Writer{primary}, others...) {
-            loggerConfig.writerLoggers[writer] = log.New(writer, "", 0)
+            loggerConfig.sinks[writer] = &sink{logger: log.New(writer, "", 0)}
+        }
+
+        return nil
+    }
+}
+
+// WithSink adds the writer with its own level, format and any other
+// SinkOptions. An UnsetLogLevel or UnsetFormat means the Logger's level or
+// format is used for the writer:
+//   WithSink(os.Stdout, WARN, JSON)
+//   WithSink(file, DEBUG, Console, SinkFilter(myFilter))
+func WithSink(
+    writer io.Writer,
+    logLevel LogLevel,
+    logFormat LogFormat,
+    options ...SinkOption,
+) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        writerSink, err := newSink(
+            writer, nil, logLevel, logFormat, options,
+        )
+        if err != nil {
+            return err
+        }
+        loggerConfig.sinks[writer] = writerSink
+
+        return nil
+    }
+}
+
+// WithOwnedWriters sets the writers the same way as WithLogWriters but the new
+// Logger takes ownership of them so they're closed when the Logger is closed.
+// These can be combined with WithLogWriters for writers it shouldn't own.
+func WithOwnedWriters(
+    primary io.WriteCloser, others ...io.WriteCloser,
+) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        for _, writer := range append([]io.WriteCloser{primary}, others...) {
+            loggerConfig.sinks[writer] = &sink{logger: log.New(writer, "", 0)}
+            loggerConfig.ownedWriters = append(
+                loggerConfig.ownedWriters, writer,
+            )
         }
 
         return nil
@@ -78,3 +158,95 @@ func WithDefaultExtras(
         return nil
     }
 }
+
+// WithContextExtractors provides one or many ContextExtractors that will be
+// run for every log made with a context by this Logger.
+func WithContextExtractors(
+    extractor ContextExtractor, extractors ...ContextExtractor,
+) LoggerOption {
+    allExtractors := append([]ContextExtractor{extractor}, extractors...)
+    return func(loggerConfig *loggerConfig) error {
+        loggerConfig.contextExtractors = append(
+            loggerConfig.contextExtractors, allExtractors...,
+        )
+
+        return nil
+    }
+}
+
+// WithFieldOrder sets the order the new Logger will output fields in.
+func WithFieldOrder(fieldOrder FieldOrder) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        loggerConfig.fieldOrder = &fieldOrder
+
+        return nil
+    }
+}
+
+// WithTimestampFormat sets how the new Logger will output timestamps.
+func WithTimestampFormat(timestampFormat TimestampFormat) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        loggerConfig.timestampFormat = &timestampFormat
+
+        return nil
+    }
+}
+
+// WithUTC sets whether the new Logger will output timestamps in UTC rather
+// than local time.
+func WithUTC(utc bool) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        loggerConfig.utc = &utc
+
+        return nil
+    }
+}
+
+// WithClock sets the Clock the new Logger will get the time from rather than
+// the global Clock.
+func WithClock(clock Clock) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        if clock == nil {
+            return errors.New("Clock cannot be nil")
+        }
+
+        loggerConfig.clock = clock
+
+        return nil
+    }
+}
+
+// WithFieldNames sets the keys the new Logger will use for the reserved parts
+// of a log.
+func WithFieldNames(fieldNames FieldNames) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        loggerConfig.fieldNames = &fieldNames
+
+        return nil
+    }
+}
+
+// WithCaller makes the new Logger capture the file, line & function each log
+// was made from and output it in a "caller" field.
+func WithCaller() LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        caller := true
+        loggerConfig.caller = &caller
+
+        return nil
+    }
+}
+
+// WithCallerSkip sets the number of extra frames the new Logger skips when
+// capturing the caller. Use this when wrapping the Logger in your own helper
+// functions so that the helper's caller is logged rather than the helper.
+func WithCallerSkip(skip int) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        if skip < 0 {
+            return errors.Errorf("Caller skip cannot be negative: %d", skip)
+        }
+        loggerConfig.callerSkip = &skip
+
+        return nil
+    }
+}
@@ -2,9 +2,14 @@
 package logging
 
 import (
+    "bufio"
+    "context"
+    "runtime"
     "math/rand"
+    "os"
     "strconv"
     "strings"
+    "sync"
     "testing"
     "errors"
 
@@ -26,7 +31,7 @@ func TestLoggerBasic(t *testing.T) {
     newLogger.Info("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
     ))
 }
 
@@ -76,7 +81,7 @@ func TestLoggerError(t *testing.T) {
     newLogger.Error("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"ERROR","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"ERROR","message":"Foo"}`,
     ))
 }
 
@@ -95,7 +100,7 @@ func TestLoggerWarn(t *testing.T) {
     newLogger.Warn("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"WARN","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"WARN","message":"Foo"}`,
     ))
 }
 
@@ -238,8 +243,8 @@ func TestLoggerJSONExtras(t *testing.T) {
     logResult := builder.String()
     t.Log("\n" + logResult)
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","test_log":"baz"` +
-            `,"timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"test_log":"baz"}`,
     ))
 }
 
@@ -260,8 +265,8 @@ func TestLoggerJSONExtraFunc(t *testing.T) {
     logResult := builder.String()
     t.Log("\n" + logResult)
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","test_log":"baz"` +
-            `,"timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"test_log":"baz"}`,
     ))
 }
 
@@ -286,8 +291,8 @@ func TestLoggerJSONExtraFuncAndStruct(t *testing.T) {
     logResult := builder.String()
     t.Log("\n" + logResult)
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","test_2":"baz2",` +
-            `"test_log":"baz","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"test_log":"baz","test_2":"baz2"}`,
     ))
 }
 
@@ -328,8 +333,39 @@ func TestLoggerException(t *testing.T) {
     t.Log(stringResult)
 
     g.Expect(stringResult).To(gm.MatchRegexp(
-        `{"error":"test err![^"]+","log_level":"ERROR",` +
-            `"message":"Oh noes, an error.","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"ERROR",` +
+            `"message":"Oh noes, an error.","error":{"message":"test err!",` +
+            `"type":"\*errors.errorString",` +
+            `"stack":\[{"file":"[^"]+/logger_test\.go",`,
+    ))
+}
+
+func TestLoggerExceptionNil(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    g.Expect(func() {
+        newLogger.Exception(nil, "Oh noes, no error.")
+    }).ToNot(gm.Panic())
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"ERROR",` +
+            `"message":"Oh noes, no error.","error":null}\n$`,
+    ))
+
+    builder.Reset()
+    newLogger.SetFormat(Standard)
+    newLogger.Exception(nil, "Oh noes, no error.")
+    g.Expect(builder.String()).To(gm.HaveSuffix(
+        " ERROR error=\"<nil>\" Oh noes, no error.\n",
     ))
 }
 
@@ -353,7 +389,7 @@ func TestLoggerGlobalExtras(t *testing.T) {
     newLogger.Info("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"foo":"bar","log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo","foo":"bar"}`,
     ))
 }
 
@@ -377,7 +413,7 @@ func TestLoggerDefaultExtras(t *testing.T) {
     newLogger.Info("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"foo":"bar","log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo","foo":"bar"}`,
     ))
 }
 
@@ -402,14 +438,14 @@ func TestLoggerDefaultExtrasFuncs(t *testing.T) {
 
     newLogger.Info("Foo")
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"foo":"bar","log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo","foo":"bar"}`,
     ))
 
     val = "baz"
 
     newLogger.Info("Foo")
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"foo":"baz","log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo","foo":"baz"}`,
     ))
 }
 
@@ -431,7 +467,7 @@ func TestLoggerAddWriter(t *testing.T) {
 
     newLogger.Info("Foo")
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
     ))
 
     g.Expect(builder2.String()).To(gm.BeEmpty())
@@ -442,10 +478,10 @@ func TestLoggerAddWriter(t *testing.T) {
 
     newLogger.Info("Bar")
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Bar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Bar"}`,
     ))
     g.Expect(builder2.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Bar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Bar"}`,
     ))
 }
 
@@ -476,7 +512,7 @@ func TestLoggerSetFormat(t *testing.T) {
 
     newLogger.Info("Bar")
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Bar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Bar"}`,
     ))
 }
 
@@ -494,7 +530,7 @@ func TestLoggerAddDefaultExtras(t *testing.T) {
 
     newLogger.Info("Foo")
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
     ))
 
     builder.Reset()
@@ -505,7 +541,7 @@ func TestLoggerAddDefaultExtras(t *testing.T) {
 
     newLogger.Info("Foo")
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"bar":"baz","log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo","bar":"baz"}`,
     ))
 }
 
@@ -527,7 +563,7 @@ func TestLoggerSetDefaultExtras(t *testing.T) {
     newLogger.Info("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"foo":"bar","log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo","foo":"bar"}`,
     ))
 
     builder.Reset()
@@ -539,8 +575,8 @@ func TestLoggerSetDefaultExtras(t *testing.T) {
     newLogger.Info("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","test":"setextras",` +
-            `"timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"test":"setextras"}`,
     ))
 }
 
@@ -565,10 +601,10 @@ func TestLoggerRemoveWriter(t *testing.T) {
 
     newLogger.Info("Foo")
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
     ))
     g.Expect(builder2.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
     ))
 
     builder.Reset()
@@ -578,7 +614,7 @@ func TestLoggerRemoveWriter(t *testing.T) {
 
     newLogger.Info("Bar")
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Bar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Bar"}`,
     ))
     g.Expect(builder2.String()).To(gm.BeEmpty())
 }
@@ -602,7 +638,7 @@ func TestLoggerSetWriters(t *testing.T) {
 
     newLogger.Info("Foo")
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foo","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
     ))
     g.Expect(builder2.String()).To(gm.BeEmpty())
 
@@ -614,7 +650,7 @@ func TestLoggerSetWriters(t *testing.T) {
     newLogger.Info("Bar")
     g.Expect(builder.String()).To(gm.BeEmpty())
     g.Expect(builder2.String()).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Bar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Bar"}`,
     ))
 }
 
@@ -638,10 +674,11 @@ func TestLoggerDefaultExtrasFailure(t *testing.T) {
     newLogger.Info("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"error":"Test extras error.[^"]+","log_level":"ERROR",` +
+        `{"timestamp":\d+,"log_level":"ERROR",` +
             `"message":"Error while running logger instance extras.",` +
-            `"timestamp":\d+}\n` +
-        `{"log_level":"INFO","message":"Foo","timestamp":\d+}`,
+            `"error":{"message":"Error while running extra #0: [^"]+: ` +
+            `Test extras error\.",.+}}\n` +
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
     ))
 }
 
@@ -667,10 +704,11 @@ func TestLoggerGlobalExtrasFailure(t *testing.T) {
     newLogger.Info("Foo")
 
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"error":"Test extras error.[^"]+","log_level":"ERROR",` +
+        `{"timestamp":\d+,"log_level":"ERROR",` +
             `"message":"Error while running global logger extras.",` +
-            `"timestamp":\d+}\n` +
-        `{"log_level":"INFO","message":"Foo","timestamp":\d+}`,
+            `"error":{"message":"Error while running extra #0: [^"]+: ` +
+            `Test extras error\.",.+}}\n` +
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
     ))
 }
 
@@ -704,8 +742,8 @@ func TestCloneLogger(t *testing.T) {
     logResult := builder.String()
     t.Log("\n" + logResult)
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"bar":10,"foo":5,"log_level":"INFO","message":"Foo",` +
-            `"timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"foo":5,"bar":10}`,
     ))
 
     builder.Reset()
@@ -715,7 +753,628 @@ func TestCloneLogger(t *testing.T) {
     logResult = builder.String()
     t.Log("\n" + logResult)
     g.Expect(builder.String()).To(gm.MatchRegexp(
-        `{"foo":5,"log_level":"INFO","message":"Foo",` +
-            `"timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"foo":5}`,
+    ))
+}
+
+func TestLoggerReservedExtras(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo", Extras{
+        "message": "bar",
+        "log_level": "baz",
+    })
+
+    logResult := builder.String()
+    t.Log("\n" + logResult)
+    g.Expect(logResult).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"fields.log_level":"baz","fields.message":"bar"}`,
+    ))
+}
+
+func TestLoggerRecordFormatter(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var records []*Record
+    identifier := "test" + strconv.Itoa(rand.Int())
+    newLogger, err := NewLogger(
+        identifier,
+        WithLogWriters(new(strings.Builder)),
+        WithFormatter(FormatterFunc(func(record *Record) ([]byte, error) {
+            records = append(records, record)
+            return nil, nil
+        })),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Warn("Foo", Extra("message", "bar"))
+
+    g.Expect(records).To(gm.HaveLen(1))
+    g.Expect(records[0].Level).To(gm.Equal(WARN))
+    g.Expect(records[0].Message).To(gm.Equal("Foo"))
+    g.Expect(records[0].LoggerIdentifier).To(gm.Equal(identifier))
+    g.Expect(records[0].Fields).To(gm.Equal([]Field{
+        {Key: "message", Value: "bar"},
+    }))
+}
+
+func TestLoggerStandardFieldOrder(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(Standard),
+        WithDefaultExtras(StaticExtras(Extras{
+            "b_default": 1,
+            "a_default": 2,
+        })),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    for i := 0; i < 10; i++ {
+        builder.Reset()
+
+        newLogger.Info("Foo", Extra("zed", "z"), Extras{
+            "beta": "b",
+            "alpha": "a",
+        })
+
+        g.Expect(builder.String()).To(gm.MatchRegexp(
+            `^[^\s]+ INFO zed="z" alpha="a" beta="b" a_default="2" ` +
+                `b_default="1" Foo\n$`,
+        ))
+    }
+}
+
+func TestLoggerSortedFieldOrder(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithFieldOrder(SortedOrder),
+        WithDefaultExtras(StaticExtras(Extras{
+            "middle": 1,
+        })),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo", Extra("zed", "z"), Extra("alpha", "a"))
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"alpha":"a","middle":1,"zed":"z"}`,
+    ))
+
+    builder.Reset()
+    newLogger.SetFieldOrder(InsertionOrder)
+
+    newLogger.Info("Foo", Extra("zed", "z"), Extra("alpha", "a"))
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"zed":"z","alpha":"a","middle":1}`,
     ))
 }
+
+// lockedBuilder is a strings.Builder which is safe for concurrent use.
+type lockedBuilder struct {
+    mutex sync.Mutex
+    builder strings.Builder
+}
+
+func (self *lockedBuilder) Write(p []byte) (int, error) {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+    return self.builder.Write(p)
+}
+
+func (self *lockedBuilder) String() string {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+    return self.builder.String()
+}
+
+func TestLoggerConcurrentReconfiguration(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder, builder2 lockedBuilder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    var waitGroup sync.WaitGroup
+    for i := 0; i < 8; i++ {
+        waitGroup.Add(1)
+        go func() {
+            defer waitGroup.Done()
+            for j := 0; j < 200; j++ {
+                newLogger.Info("Foo", Extra("j", j))
+                newLogger.Debug("Bar")
+            }
+        }()
+    }
+
+    waitGroup.Add(1)
+    go func() {
+        defer waitGroup.Done()
+        for j := 0; j < 200; j++ {
+            newLogger.AddWriters(&builder2)
+            newLogger.RemoveWriter(&builder2)
+            newLogger.SetWriters(&builder, &builder2)
+            newLogger.SetFormat(Standard)
+            newLogger.SetFormat(JSON)
+            newLogger.SetFieldOrder(SortedOrder)
+            _ = newLogger.SetLogLevel(DEBUG)
+            _ = newLogger.SetLogLevel(INFO)
+            newLogger.AddDefaultExtras(StaticExtras(Extras{"k": j}))
+            newLogger.SetDefaultExtras(StaticExtras(Extras{"k": j}))
+        }
+    }()
+
+    waitGroup.Add(1)
+    go func() {
+        defer waitGroup.Done()
+        for j := 0; j < 200; j++ {
+            AddGlobalExtras(StaticExtras(Extras{"global": j}))
+        }
+        SetGlobalExtras()
+    }()
+
+    waitGroup.Wait()
+
+    g.Expect(builder.String()).To(gm.ContainSubstring("Foo"))
+}
+
+func TestLoggerConcurrentClone(t *testing.T) {
+    // NOTE: Console is included since its formatter is resolved for each
+    //       writer when a logger's state is made.
+    for _, format := range []LogFormat{JSON, Console} {
+        testLoggerConcurrentClone(t, format)
+    }
+}
+
+func testLoggerConcurrentClone(t *testing.T, format LogFormat) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder lockedBuilder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(format),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    var waitGroup sync.WaitGroup
+    for i := 0; i < 8; i++ {
+        waitGroup.Add(1)
+        go func() {
+            defer waitGroup.Done()
+            newLogger.Info("Bar")
+            clonedLogger, err := CloneLogger(
+                "test" + strconv.Itoa(rand.Int()),
+                newLogger,
+                WithDefaultExtras(StaticExtras(Extras{"clone": true})),
+            )
+            if err != nil {
+                t.Error(err)
+                return
+            }
+            defer clonedLogger.Close()
+            clonedLogger.Info("Foo")
+        }()
+        newLogger.AddDefaultExtras(StaticExtras(Extras{"base": i}))
+    }
+
+    waitGroup.Wait()
+}
+
+func TestLoggerTrace(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithLogLevel(DEBUG),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Trace("Foo")
+    g.Expect(builder.String()).To(gm.BeEmpty())
+
+    err = newLogger.SetLogLevel(TRACE)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    newLogger.Trace("Foo")
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"TRACE","message":"Foo"}`,
+    ))
+}
+
+// syncBuilder is a strings.Builder which records whether it was synced.
+type syncBuilder struct {
+    strings.Builder
+    synced bool
+}
+
+func (self *syncBuilder) Sync() error {
+    self.synced = true
+    return nil
+}
+
+func TestLoggerFatal(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    exitCode := -1
+    exitFunc = func(code int) {
+        exitCode = code
+    }
+    defer func() {
+        exitFunc = os.Exit
+    }()
+
+    var builder syncBuilder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Fatal("Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"FATAL","message":"Foo"}`,
+    ))
+    g.Expect(builder.synced).To(gm.BeTrue())
+    g.Expect(exitCode).To(gm.Equal(1))
+}
+
+func TestLoggerPanic(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    g.Expect(func() {
+        newLogger.Panic("Foo")
+    }).To(gm.Panic())
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"PANIC","message":"Foo"}`,
+    ))
+}
+
+func TestLoggerFormatted(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithLogLevel(TRACE),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Tracef("Foo %d", 1)
+    newLogger.Debugf("Foo %d", 2)
+    newLogger.Infof("Foo %d", 3)
+    newLogger.Warnf("Foo %d", 4)
+    newLogger.Errorf("Foo %s", "five")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"TRACE","message":"Foo 1"}\n` +
+            `{"timestamp":\d+,"log_level":"DEBUG","message":"Foo 2"}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Foo 3"}\n` +
+            `{"timestamp":\d+,"log_level":"WARN","message":"Foo 4"}\n` +
+            `{"timestamp":\d+,"log_level":"ERROR","message":"Foo five"}\n`,
+    ))
+}
+
+func TestLoggerKeysAndValues(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Infow("Foo", "user", "bar", "attempt", 2)
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"user":"bar","attempt":2}`,
+    ))
+}
+
+func TestLoggerKeysAndValuesExtras(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Warnw("Foo", "user", "bar", Extras{"baz": 1})
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"WARN","message":"Foo",` +
+            `"user":"bar","baz":1}`,
+    ))
+}
+
+func TestLoggerKeysAndValuesMalformed(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Errorw("Foo", "user", "bar", "dangling")
+    newLogger.Errorw("Foo", 5, "user", "bar", "dangling")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"ERROR","message":"Foo",` +
+            `"user":"bar","!BADKEY":"dangling"}\n` +
+            `{"timestamp":\d+,"log_level":"ERROR","message":"Foo",` +
+            `"user":"bar","!BADKEY":\[5,"dangling"\]}\n`,
+    ))
+}
+
+func TestLoggerWith(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    child := newLogger.With(Extras{"request_id": "abc"})
+    grandchild := child.WithFields("user", "bar", "request_id", "def")
+
+    child.Info("Foo", Extras{"baz": 1})
+    grandchild.Info("Foo")
+    grandchild.Info("Foo", Extras{"user": "override"})
+    newLogger.Info("Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"request_id":"abc","baz":1}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"request_id":"def","user":"bar"}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Foo",` +
+            `"request_id":"def","user":"override"}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}\n`,
+    ))
+}
+
+func TestLoggerWithSharesConfig(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    identifier := "test" + strconv.Itoa(rand.Int())
+    newLogger, err := NewLogger(
+        identifier,
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    child := newLogger.With(Extras{"foo": "bar"})
+    g.Expect(child.Identifier()).To(gm.Equal(identifier))
+
+    err = newLogger.SetLogLevel(ERROR)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    newLogger.SetFormat(Standard)
+
+    child.Info("Foo")
+    child.Error("Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2} ERROR foo="bar" Foo\n$`,
+    ))
+
+    // NOTE: Children aren't registered so closing one mustn't remove its
+    //       parent.
+    child.Close()
+    g.Expect(GetLogger(identifier)).To(gm.BeIdenticalTo(newLogger))
+}
+
+func TestLoggerCaller(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithCaller(),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    _, _, line, _ := runtime.Caller(0)
+    newLogger.Info("Foo")
+    newLogger.Infof("Foo")
+    newLogger.Infow("Foo")
+    newLogger.InfoContext(context.Background(), "Foo")
+    newLogger.LogAt(INFO, "Foo")
+    newLogger.With(Extras{"bar": 1}).Info("Foo")
+    newLogger.Exception(errors.New("Bar"), "Foo")
+
+    lines := strings.Split(strings.TrimSpace(builder.String()), "\n")
+    g.Expect(lines).To(gm.HaveLen(7))
+    for i, logLine := range lines {
+        g.Expect(logLine).To(gm.MatchRegexp(
+            `"caller":{"file":"[^"]+/logger_test\.go","line":%d,` +
+                `"function":"github\.com/daihasso/slogging\.` +
+                `TestLoggerCaller"}`,
+            line + i + 1,
+        ))
+    }
+}
+
+func TestLoggerCallerSkip(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(Standard),
+        WithCaller(),
+        WithCallerSkip(1),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    logHelper := func(message string) {
+        newLogger.Info(message)
+    }
+
+    _, _, line, _ := runtime.Caller(0)
+    logHelper("Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2} INFO ` +
+            `caller="[^/"]+/logger_test\.go:%d slogging\.` +
+            `TestLoggerCallerSkip" Foo\n$`,
+        line + 1,
+    ))
+
+    builder.Reset()
+    newLogger.SetCaller(false)
+    logHelper("Foo")
+
+    g.Expect(builder.String()).ToNot(gm.ContainSubstring("caller"))
+}
+
+// trackingWriter records whether it has been flushed or closed.
+type trackingWriter struct {
+    lockedBuilder
+    flushed int
+    closed int
+}
+
+func (self *trackingWriter) Flush() error {
+    self.flushed++
+    return nil
+}
+
+func (self *trackingWriter) Close() error {
+    self.closed++
+    return nil
+}
+
+func TestLoggerFlush(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    bufferedWriter := bufio.NewWriter(&builder)
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(bufferedWriter),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo")
+    g.Expect(builder.String()).To(gm.BeEmpty())
+
+    g.Expect(newLogger.Flush()).To(gm.Succeed())
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foo"}`,
+    ))
+}
+
+func TestLoggerCloseOwnedWriters(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    owned, notOwned, added := new(trackingWriter), new(trackingWriter),
+        new(trackingWriter)
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithOwnedWriters(owned),
+        WithLogWriters(notOwned),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    newLogger.AddOwnedWriters(added)
+
+    clonedLogger, err := CloneLogger(
+        "test" + strconv.Itoa(rand.Int()), newLogger,
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(clonedLogger.Close()).To(gm.Succeed())
+    g.Expect(owned.closed).To(gm.BeZero())
+
+    newLogger.With(Extras{"foo": "bar"}).Close()
+    g.Expect(owned.closed).To(gm.BeZero())
+    g.Expect(GetLogger(newLogger.Identifier())).To(
+        gm.BeIdenticalTo(newLogger),
+    )
+
+    g.Expect(newLogger.Close()).To(gm.Succeed())
+    g.Expect(owned.closed).To(gm.Equal(1))
+    g.Expect(added.closed).To(gm.Equal(1))
+    g.Expect(notOwned.closed).To(gm.BeZero())
+    g.Expect(notOwned.flushed).To(gm.BeNumerically(">", 0))
+
+    // NOTE: Closing again mustn't close the writers twice.
+    g.Expect(newLogger.Close()).To(gm.Succeed())
+    g.Expect(owned.closed).To(gm.Equal(1))
+}
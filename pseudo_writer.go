@@ -1,32 +1,192 @@
 package logging
 
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+)
+
 // PseudoWriter is a wrapper for JSONLogger for things that
 // need a writer to output.
+//
+// Writes are buffered until a newline so each complete line is logged as its
+// own log no matter how it was split across writes. Any partial line left
+// over is logged by Flush or Close.
 type PseudoWriter struct {
 	logger   *Logger
 	logLevel LogLevel
+
+	// levelPrefixes are checked (longest first) against the start of each
+	// line to decide its level.
+	levelPrefixes []levelPrefix
+	// detectLevel uses the same "[LEVEL]" or "LEVEL:" prefixes as
+	// NewStdLogger to decide the level of each line.
+	detectLevel bool
+
+	mutex  sync.Mutex
+	buffer []byte
+}
+
+type levelPrefix struct {
+	prefix   string
+	logLevel LogLevel
 }
 
-// Write satisfies the io.Writer interface and writes the the logger it wraps.
-func (self PseudoWriter) Write(p []byte) (n int, err error) {
-	switch self.logLevel {
-	case ERROR:
-		self.logger.Error(string(p))
-	case WARN:
-		self.logger.Warn(string(p))
-	case INFO:
-		self.logger.Info(string(p))
-	case DEBUG:
-		self.logger.Debug(string(p))
+// PseudoWriterOption is an option used when creating a new PseudoWriter.
+type PseudoWriterOption func(*PseudoWriter)
+
+// PseudoWriterLevelPrefixes logs lines starting with one of the prefixes at
+// its LogLevel instead of the PseudoWriter's (with the prefix and any spaces
+// after it removed). Lines logged at FATAL or PANIC this way don't exit or
+// panic; only the PseudoWriter's own level does:
+//   logging.PseudoWriterLevelPrefixes(map[string]logging.LogLevel{
+//       "[WARN]": logging.WARN,
+//       "E ": logging.ERROR,
+//   })
+func PseudoWriterLevelPrefixes(
+	prefixes map[string]LogLevel,
+) PseudoWriterOption {
+	return func(pseudoWriter *PseudoWriter) {
+		for prefix, logLevel := range prefixes {
+			pseudoWriter.levelPrefixes = append(
+				pseudoWriter.levelPrefixes, levelPrefix{prefix, logLevel},
+			)
+		}
+		// NOTE: Longer prefixes are checked first so "[WARNING]" wins over
+		//       "[WARN" for example.
+		sort.SliceStable(pseudoWriter.levelPrefixes, func(i, j int) bool {
+			iPrefix := pseudoWriter.levelPrefixes[i].prefix
+			jPrefix := pseudoWriter.levelPrefixes[j].prefix
+			if len(iPrefix) != len(jPrefix) {
+				return len(iPrefix) > len(jPrefix)
+			}
+			return iPrefix < jPrefix
+		})
 	}
+}
+
+// PseudoWriterDetectLevel logs lines starting with a level in the form
+// "[LEVEL]" or "LEVEL:" (ignoring case) at that level instead of the
+// PseudoWriter's, the same as NewStdLogger. Like PseudoWriterLevelPrefixes a
+// detected FATAL or PANIC level doesn't exit or panic.
+func PseudoWriterDetectLevel() PseudoWriterOption {
+	return func(pseudoWriter *PseudoWriter) {
+		pseudoWriter.detectLevel = true
+	}
+}
+
+// Write satisfies the io.Writer interface and writes each complete line to the
+// logger it wraps.
+func (self *PseudoWriter) Write(p []byte) (n int, err error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.buffer = append(self.buffer, p...)
+	for {
+		end := bytes.IndexByte(self.buffer, '\n')
+		if end == -1 {
+			break
+		}
+		line := string(self.buffer[:end])
+		self.buffer = self.buffer[end+1:]
+
+		self.logLine(0, line)
+	}
+	if len(self.buffer) == 0 {
+		// NOTE: Drop the consumed bytes so the buffer doesn't keep growing.
+		self.buffer = nil
+	}
+
 	return len(p), nil
 }
 
+// Flush logs any partial line which is waiting for a newline.
+func (self *PseudoWriter) Flush() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.flush(0)
+
+	return nil
+}
+
+// Close logs any partial line which is waiting for a newline.
+func (self *PseudoWriter) Close() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.flush(0)
+
+	return nil
+}
+
+func (self *PseudoWriter) flush(skip int) {
+	if len(self.buffer) == 0 {
+		return
+	}
+	line := string(self.buffer)
+	self.buffer = nil
+
+	self.logLine(skip+1, line)
+}
+
+// logLine logs a single line (ignoring empty ones). skip is the number of
+// frames between the function calling logLine and the user's code.
+func (self *PseudoWriter) logLine(skip int, line string) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return
+	}
+	logLevel, line, detected := self.lineLevel(line)
+
+	// NOTE: The internal methods are used so the caller is whoever wrote to
+	//       this writer rather than the PseudoWriter. Only the writer's own
+	//       level exits or panics; a level detected from what's written (like
+	//       a subprocess printing "fatal: ...") is just logged.
+	switch {
+	case detected:
+		self.logger.logToLevel(skip+1, logLevel, line, nil)
+	case logLevel == PANIC:
+		self.logger.logToLevel(skip+1, PANIC, line, nil)
+		panic(line)
+	case logLevel == FATAL:
+		self.logger.fatal(skip+1, line, nil)
+	default:
+		self.logger.logToLevel(skip+1, logLevel, line, nil)
+	}
+}
+
+// lineLevel gets the level for the line from its prefix (if configured), the
+// line without the prefix and whether the level came from the prefix.
+func (self *PseudoWriter) lineLevel(line string) (LogLevel, string, bool) {
+	for _, levelPrefix := range self.levelPrefixes {
+		if strings.HasPrefix(line, levelPrefix.prefix) {
+			return levelPrefix.logLevel, strings.TrimLeft(
+				line[len(levelPrefix.prefix):], " \t",
+			), true
+		}
+	}
+	if self.detectLevel {
+		logLevel, rest := parseLevelPrefix(line, self.logLevel)
+		return logLevel, rest, rest != line
+	}
+
+	return self.logLevel, line, false
+}
+
 // NewPsuedoWriter wraps a logger with the Write functionality wich writes out
 // logs at a specified log level.
-func NewPseudoWriter(logLevel LogLevel, logger *Logger) *PseudoWriter {
-    return &PseudoWriter{
-        logger: logger,
-        logLevel: logLevel,
-    }
+func NewPseudoWriter(
+	logLevel LogLevel, logger *Logger, options ...PseudoWriterOption,
+) *PseudoWriter {
+	pseudoWriter := &PseudoWriter{
+		logger:   logger,
+		logLevel: logLevel,
+	}
+	for _, option := range options {
+		option(pseudoWriter)
+	}
+
+	return pseudoWriter
 }
@@ -2,7 +2,9 @@
 package logging
 
 import (
+    "runtime"
     "math/rand"
+    "os"
     "strconv"
     "strings"
     "testing"
@@ -24,13 +26,13 @@ func TestPsuedoWriter(t *testing.T) {
 
     psuedoWriter := NewPseudoWriter(INFO, newLogger)
 
-    _, err = psuedoWriter.Write([]byte("Foobar"))
+    _, err = psuedoWriter.Write([]byte("Foobar\n"))
     g.Expect(err).ToNot(gm.HaveOccurred())
 
     logResult := builder.String()
     t.Log("\n" + logResult)
     g.Expect(logResult).To(gm.MatchRegexp(
-        `{"log_level":"INFO","message":"Foobar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"INFO","message":"Foobar"}`,
     ))
 }
 
@@ -49,13 +51,13 @@ func TestPsuedoWriterDebug(t *testing.T) {
 
     psuedoWriter := NewPseudoWriter(DEBUG, newLogger)
 
-    _, err = psuedoWriter.Write([]byte("Foobar"))
+    _, err = psuedoWriter.Write([]byte("Foobar\n"))
     g.Expect(err).ToNot(gm.HaveOccurred())
 
     logResult := builder.String()
     t.Log("\n" + logResult)
     g.Expect(logResult).To(gm.MatchRegexp(
-        `{"log_level":"DEBUG","message":"Foobar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"DEBUG","message":"Foobar"}`,
     ))
 }
 
@@ -73,13 +75,13 @@ func TestPsuedoWriterWarn(t *testing.T) {
 
     psuedoWriter := NewPseudoWriter(WARN, newLogger)
 
-    _, err = psuedoWriter.Write([]byte("Foobar"))
+    _, err = psuedoWriter.Write([]byte("Foobar\n"))
     g.Expect(err).ToNot(gm.HaveOccurred())
 
     logResult := builder.String()
     t.Log("\n" + logResult)
     g.Expect(logResult).To(gm.MatchRegexp(
-        `{"log_level":"WARN","message":"Foobar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"WARN","message":"Foobar"}`,
     ))
 }
 
@@ -97,12 +99,234 @@ func TestPsuedoWriterError(t *testing.T) {
 
     psuedoWriter := NewPseudoWriter(ERROR, newLogger)
 
-    _, err = psuedoWriter.Write([]byte("Foobar"))
+    _, err = psuedoWriter.Write([]byte("Foobar\n"))
     g.Expect(err).ToNot(gm.HaveOccurred())
 
     logResult := builder.String()
     t.Log("\n" + logResult)
     g.Expect(logResult).To(gm.MatchRegexp(
-        `{"log_level":"ERROR","message":"Foobar","timestamp":\d+}`,
+        `{"timestamp":\d+,"log_level":"ERROR","message":"Foobar"}`,
+    ))
+}
+
+func TestPsuedoWriterTrace(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithLogLevel(TRACE),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    psuedoWriter := NewPseudoWriter(TRACE, newLogger)
+
+    _, err = psuedoWriter.Write([]byte("Foobar\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    logResult := builder.String()
+    t.Log("\n" + logResult)
+    g.Expect(logResult).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"TRACE","message":"Foobar"}`,
+    ))
+}
+
+func TestPsuedoWriterCaller(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithCaller(),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    psuedoWriter := NewPseudoWriter(INFO, newLogger)
+
+    _, _, line, _ := runtime.Caller(0)
+    _, err = psuedoWriter.Write([]byte("Foobar\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `"caller":{"file":"[^"]+/psuedo_writer_test\.go","line":%d,`,
+        line + 1,
     ))
 }
+
+func TestPsuedoWriterLines(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    psuedoWriter := NewPseudoWriter(INFO, newLogger)
+
+    for _, chunk := range []string{"Fo", "o\nBar\r\n\nBa", "z"} {
+        n, err := psuedoWriter.Write([]byte(chunk))
+        g.Expect(err).ToNot(gm.HaveOccurred())
+        g.Expect(n).To(gm.Equal(len(chunk)))
+    }
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"INFO","message":"Foo"}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Bar"}\n$`,
+    ))
+
+    g.Expect(psuedoWriter.Close()).To(gm.Succeed())
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `\n{"timestamp":\d+,"log_level":"INFO","message":"Baz"}\n$`,
+    ))
+
+    // NOTE: Nothing is left to flush.
+    builder.Reset()
+    g.Expect(psuedoWriter.Flush()).To(gm.Succeed())
+    g.Expect(builder.String()).To(gm.BeEmpty())
+}
+
+func TestPsuedoWriterLevelPrefixes(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    psuedoWriter := NewPseudoWriter(
+        INFO,
+        newLogger,
+        PseudoWriterLevelPrefixes(map[string]LogLevel{
+            "[WARN": INFO,
+            "[WARNING]": WARN,
+            "E ": ERROR,
+        }),
+    )
+
+    _, err = psuedoWriter.Write(
+        []byte("[WARNING]  Foo\nE Bar\n[error] Baz\n"),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"WARN","message":"Foo"}\n` +
+            `{"timestamp":\d+,"log_level":"ERROR","message":"Bar"}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"\[error\] Baz"}\n$`,
+    ))
+}
+
+func TestPsuedoWriterDetectLevel(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    psuedoWriter := NewPseudoWriter(
+        INFO, newLogger, PseudoWriterDetectLevel(),
+    )
+
+    _, err = psuedoWriter.Write([]byte("[WARN] Foo\nerror: Bar\nBaz\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"WARN","message":"Foo"}\n` +
+            `{"timestamp":\d+,"log_level":"ERROR","message":"Bar"}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Baz"}\n$`,
+    ))
+}
+
+func TestPsuedoWriterFlushCaller(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithCaller(),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    psuedoWriter := NewPseudoWriter(INFO, newLogger)
+    _, err = psuedoWriter.Write([]byte("Foobar"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    _, _, line, _ := runtime.Caller(0)
+    g.Expect(psuedoWriter.Flush()).To(gm.Succeed())
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `"caller":{"file":"[^"]+/psuedo_writer_test\.go","line":%d,`,
+        line + 1,
+    ))
+}
+
+func TestPsuedoWriterDetectedLevelsDontExit(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    exitCode := -1
+    exitFunc = func(code int) {
+        exitCode = code
+    }
+    defer func() {
+        exitFunc = os.Exit
+    }()
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    psuedoWriter := NewPseudoWriter(
+        INFO,
+        newLogger,
+        PseudoWriterDetectLevel(),
+        PseudoWriterLevelPrefixes(map[string]LogLevel{"!! ": PANIC}),
+    )
+
+    g.Expect(func() {
+        _, err = psuedoWriter.Write([]byte(
+            "fatal: not a git repository\npanic: boom\n!! Oh no\n",
+        ))
+    }).ToNot(gm.Panic())
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(exitCode).To(gm.Equal(-1))
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"FATAL",` +
+            `"message":"not a git repository"}\n` +
+            `{"timestamp":\d+,"log_level":"PANIC","message":"boom"}\n` +
+            `{"timestamp":\d+,"log_level":"PANIC","message":"Oh no"}\n$`,
+    ))
+
+    // NOTE: The writer's own level still exits.
+    _, err = NewPseudoWriter(
+        FATAL, newLogger, PseudoWriterDetectLevel(),
+    ).Write([]byte("Foo\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(exitCode).To(gm.Equal(1))
+}
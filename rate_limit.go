@@ -0,0 +1,138 @@
+package logging
+
+import (
+    "sync"
+    "time"
+
+    "github.com/pkg/errors"
+)
+
+// Keys & message used for the logs reporting how many logs were dropped by
+// rate limiting.
+const (
+    rateLimitReportMessage = "Logs were dropped by rate limiting"
+    rateLimitedCountKey = "dropped_count"
+)
+
+// WithRateLimit limits the new Logger to an average of perSecond logs a
+// second with bursts of up to burst logs; anything over the limit is dropped.
+// Unlike sampling the limit applies to all logs regardless of their message.
+//
+// The number of logs dropped is reported (at the level of the most severe
+// log dropped) with the next log which is allowed and when the Logger is
+// flushed or closed. FATAL and PANIC logs are never dropped.
+func WithRateLimit(perSecond float64, burst int) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        if perSecond <= 0 {
+            return errors.Errorf("Rate limit must be positive: %g", perSecond)
+        }
+        if burst < 1 {
+            return errors.Errorf("Rate limit burst must be positive: %d", burst)
+        }
+        loggerConfig.rateLimit = &rateLimit{
+            perSecond: perSecond,
+            burst: burst,
+        }
+
+        return nil
+    }
+}
+
+type rateLimit struct {
+    perSecond float64
+    burst int
+}
+
+// rateLimitReport is the number of logs dropped by a rateLimiter.
+type rateLimitReport struct {
+    level LogLevel
+    dropped int
+}
+
+// rateLimiter is a token bucket; each log takes a token and tokens are added
+// at a constant rate up to the size of the burst.
+type rateLimiter struct {
+    limit rateLimit
+
+    mutex sync.Mutex
+    tokens float64
+    last time.Time
+    dropped int
+    // droppedLevel is the level of the most severe log dropped.
+    droppedLevel LogLevel
+    droppedSeverity int
+}
+
+func newRateLimiter(limit rateLimit) *rateLimiter {
+    return &rateLimiter{
+        limit: limit,
+        tokens: float64(limit.burst),
+    }
+}
+
+// allow takes a token if there is one and gets the report for the logs
+// dropped before it if there were any.
+func (self *rateLimiter) allow(
+    level LogLevel, now time.Time,
+) (bool, *rateLimitReport) {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+
+    if !self.last.IsZero() && now.After(self.last) {
+        self.tokens += now.Sub(self.last).Seconds() * self.limit.perSecond
+        if burst := float64(self.limit.burst); self.tokens > burst {
+            self.tokens = burst
+        }
+    }
+    if self.last.IsZero() || now.After(self.last) {
+        self.last = now
+    }
+
+    if self.tokens < 1 {
+        severity, _ := level.Severity()
+        if self.dropped == 0 || severity > self.droppedSeverity {
+            self.droppedLevel, self.droppedSeverity = level, severity
+        }
+        self.dropped++
+
+        return false, nil
+    }
+    self.tokens--
+
+    return true, self.report()
+}
+
+// drain gets the report for the logs dropped since the last report if there
+// were any.
+func (self *rateLimiter) drain() *rateLimitReport {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+
+    return self.report()
+}
+
+func (self *rateLimiter) report() *rateLimitReport {
+    if self.dropped == 0 {
+        return nil
+    }
+    report := &rateLimitReport{
+        level: self.droppedLevel,
+        dropped: self.dropped,
+    }
+    self.dropped = 0
+
+    return report
+}
+
+func (self *Logger) writeRateLimitReport(
+    state *loggerState, report *rateLimitReport,
+) {
+    if report == nil {
+        return
+    }
+
+    fields := []Field{{Key: rateLimitedCountKey, Value: report.dropped}}
+    self.writeRecord(nil, state, self.newRecord(
+        state, report.level, rateLimitReportMessage, fields,
+    ))
+}
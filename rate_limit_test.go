@@ -0,0 +1,77 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "math/rand"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestLoggerRateLimit(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    clock := NewFixedClock(time.Unix(1552143590, 0))
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(Logfmt),
+        WithClock(clock),
+        WithUTC(true),
+        WithRateLimit(2, 2),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo")
+    newLogger.Info("Bar")
+    newLogger.Info("Baz")
+    newLogger.Warn("Baz")
+    newLogger.Info("Baz")
+    g.Expect(builder.String()).To(gm.Equal(
+        "ts=2019-03-09T14:59:50Z level=info msg=Foo\n" +
+            "ts=2019-03-09T14:59:50Z level=info msg=Bar\n",
+    ))
+
+    // NOTE: Half a second adds a single token.
+    builder.Reset()
+    clock.Advance(500 * time.Millisecond)
+    newLogger.Info("Qux")
+    newLogger.Info("Quux")
+    g.Expect(builder.String()).To(gm.Equal(
+        "ts=2019-03-09T14:59:50.5Z level=warn " +
+            "msg=\"Logs were dropped by rate limiting\" dropped_count=3\n" +
+            "ts=2019-03-09T14:59:50.5Z level=info msg=Qux\n",
+    ))
+
+    builder.Reset()
+    g.Expect(newLogger.Flush()).To(gm.Succeed())
+    g.Expect(newLogger.Flush()).To(gm.Succeed())
+    g.Expect(builder.String()).To(gm.Equal(
+        "ts=2019-03-09T14:59:50.5Z level=info " +
+            "msg=\"Logs were dropped by rate limiting\" dropped_count=1\n",
+    ))
+
+    // NOTE: Tokens never build up beyond the burst.
+    builder.Reset()
+    clock.Advance(time.Hour)
+    for i := 0; i < 3; i++ {
+        newLogger.Info("Foo")
+    }
+    g.Expect(strings.Count(builder.String(), "msg=Foo")).To(gm.Equal(2))
+}
+
+func TestWithRateLimitErrors(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    for _, option := range []LoggerOption{
+        WithRateLimit(0, 1), WithRateLimit(1, 0),
+    } {
+        _, err := NewLogger("test" + strconv.Itoa(rand.Int()), option)
+        g.Expect(err).To(gm.HaveOccurred())
+    }
+}
@@ -0,0 +1,192 @@
+package logging
+
+import (
+    "fmt"
+    "path/filepath"
+    "runtime"
+    "sort"
+    "strings"
+    "time"
+)
+
+type (
+    // Field is a single key, value pair attached to a Record.
+    Field struct {
+        Key string
+        Value interface{}
+    }
+
+    // Caller describes the location in code a log was made from.
+    Caller struct {
+        File string `json:"file"`
+        Line int `json:"line"`
+        Function string `json:"function"`
+        // pc is the program counter for the Caller if it's known.
+        pc uintptr
+    }
+
+    // Record is a single log entry as it travels through a Logger. It holds
+    // everything known about the log before it is turned into bytes by a
+    // Formatter.
+    //
+    // The reserved parts of a log (time, level & message) are kept separate
+    // from the user provided Fields so that they can never be overwritten by
+    // an extra with the same key.
+    Record struct {
+        Time time.Time
+        Level LogLevel
+        Message string
+        LoggerIdentifier string
+        // Caller is nil unless caller information has been captured.
+        Caller *Caller
+        // Fields holds all the extras for the log in the order they were
+        // applied.
+        Fields []Field
+        // TimestampFormat is how the Logger which made this Record wants Time
+        // to be output.
+        TimestampFormat TimestampFormat
+        // FieldNames are the keys the Logger which made this Record wants
+        // used for the reserved parts of the log. Unset names should use the
+        // format's defaults.
+        FieldNames FieldNames
+    }
+)
+
+// Reserved keys for the parts of a Record which aren't Fields.
+const (
+    timestampKey = "timestamp"
+    logLevelKey = "log_level"
+    messageKey = "message"
+    callerKey = "caller"
+)
+
+// newCaller gets the Caller for the function skip frames above the one calling
+// newCaller; a skip of 0 is the function calling newCaller.
+func newCaller(skip int) *Caller {
+    var pcs [1]uintptr
+    if runtime.Callers(skip + 2, pcs[:]) == 0 {
+        return nil
+    }
+
+    return callerFromPC(pcs[0])
+}
+
+// callerFromPC gets the Caller for the provided program counter.
+func callerFromPC(pc uintptr) *Caller {
+    frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+    return &Caller{
+        File: frame.File,
+        Line: frame.Line,
+        Function: frame.Function,
+        pc: pc,
+    }
+}
+
+// String outputs the caller as its file (trimmed to the containing directory)
+// and line followed by its function (trimmed to the package name):
+//   logging/logger.go:42 logging.(*Logger).Info
+func (self *Caller) String() string {
+    file := self.File
+    if dir := filepath.Dir(file); dir != "." {
+        file = filepath.Join(filepath.Base(dir), filepath.Base(file))
+    }
+    function := self.Function
+    if lastSlash := strings.LastIndex(function, "/"); lastSlash != -1 {
+        function = function[lastSlash + 1:]
+    }
+
+    return fmt.Sprintf("%s:%d %s", file, self.Line, function)
+}
+
+// reservedFieldPrefix is prepended to the key of any Field which would
+// otherwise collide with a reserved key in keyed formats.
+const reservedFieldPrefix = "fields."
+
+// safeFieldKey makes sure a Field's key doesn't collide with any of the
+// provided reserved keys.
+func safeFieldKey(key string, reservedKeys []string) string {
+    for _, reservedKey := range reservedKeys {
+        if key == reservedKey {
+            return reservedFieldPrefix + key
+        }
+    }
+
+    return key
+}
+
+// FieldOrder determines the order a Record's Fields will be output in.
+type FieldOrder int
+
+// Definition of the available FieldOrders.
+const (
+    // InsertionOrder outputs fields in the order they were applied; per-log
+    // Extras first, then the Logger's default extras and finally the global
+    // extras. Keys within a single Extras map are sorted.
+    InsertionOrder FieldOrder = iota
+    // SortedOrder outputs fields sorted alphabetically by key.
+    SortedOrder
+)
+
+// appendExtras appends the key, value pairs from extras to fields. Since
+// Extras is a map its keys are appended in sorted order so the result is
+// deterministic. If a key already exists its value is replaced in place so
+// that its position is preserved.
+func appendExtras(fields []Field, extras Extras) []Field {
+    keys := make([]string, 0, len(extras))
+    for key := range extras {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+
+    for _, key := range keys {
+        fields = setField(fields, key, extras[key])
+    }
+
+    return fields
+}
+
+// sortFields sorts fields alphabetically by key in place.
+func sortFields(fields []Field) {
+    sort.SliceStable(fields, func(i, j int) bool {
+        return fields[i].Key < fields[j].Key
+    })
+}
+
+func setField(fields []Field, key string, value interface{}) []Field {
+    for i := range fields {
+        if fields[i].Key == key {
+            fields[i].Value = value
+            return fields
+        }
+    }
+
+    return append(fields, Field{Key: key, Value: value})
+}
+
+// boundFields is a node in a chain of fields bound to a Logger by With. Each
+// child only holds the fields it added so making one is cheap regardless of
+// how deeply it is nested.
+type boundFields struct {
+    parent *boundFields
+    fields []Field
+}
+
+// collect makes a new slice of all the fields in the chain; the fields of
+// parents come first and values from children replace those of their
+// parents.
+func (self *boundFields) collect() []Field {
+    var chain []*boundFields
+    for node := self; node != nil; node = node.parent {
+        chain = append(chain, node)
+    }
+
+    var fields []Field
+    for i := len(chain) - 1; i >= 0; i-- {
+        for _, field := range chain[i].fields {
+            fields = setField(fields, field.Key, field.Value)
+        }
+    }
+
+    return fields
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+    "testing"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestAppendExtrasOverride(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    fields := appendExtras(nil, Extra("foo", 1))
+    fields = appendExtras(fields, Extra("bar", 2))
+    fields = appendExtras(fields, Extra("foo", 3))
+
+    g.Expect(fields).To(gm.Equal([]Field{
+        {Key: "foo", Value: 3},
+        {Key: "bar", Value: 2},
+    }))
+}
+
+func TestSafeFieldKey(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    reservedKeys := defaultFieldNames.reservedKeys()
+    g.Expect(safeFieldKey("foo", reservedKeys)).To(gm.Equal("foo"))
+    g.Expect(safeFieldKey("message", reservedKeys)).To(
+        gm.Equal("fields.message"),
+    )
+    g.Expect(safeFieldKey("timestamp", reservedKeys)).To(
+        gm.Equal("fields.timestamp"),
+    )
+}
+
+func TestBoundFieldsCollect(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    parent := &boundFields{
+        fields: []Field{{Key: "foo", Value: 1}, {Key: "bar", Value: 2}},
+    }
+    child := &boundFields{
+        parent: parent,
+        fields: []Field{{Key: "baz", Value: 3}, {Key: "foo", Value: 4}},
+    }
+
+    g.Expect(child.collect()).To(gm.Equal([]Field{
+        {Key: "foo", Value: 4},
+        {Key: "bar", Value: 2},
+        {Key: "baz", Value: 3},
+    }))
+    g.Expect(parent.collect()).To(gm.Equal([]Field{
+        {Key: "foo", Value: 1},
+        {Key: "bar", Value: 2},
+    }))
+
+    var empty *boundFields
+    g.Expect(empty.collect()).To(gm.BeEmpty())
+}
+
+func TestCallerString(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    caller := &Caller{
+        File: "/home/foo/go/src/github.com/foo/bar/baz.go",
+        Line: 42,
+        Function: "github.com/foo/bar.(*Baz).Qux",
+    }
+
+    g.Expect(caller.String()).To(gm.Equal("bar/baz.go:42 bar.(*Baz).Qux"))
+}
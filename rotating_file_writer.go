@@ -0,0 +1,451 @@
+package logging
+
+import (
+    "compress/gzip"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/pkg/errors"
+)
+
+// backupTimeLayout is the layout of the timestamp added to the name of
+// rotated files.
+const backupTimeLayout = "2006-01-02T15-04-05.000"
+
+// compressedSuffix is added to the name of compressed backups.
+const compressedSuffix = ".gz"
+
+// ErrRotatingFileWriterClosed is returned when writing to a
+// RotatingFileWriter which has been closed.
+var ErrRotatingFileWriterClosed = errors.New("RotatingFileWriter is closed")
+
+type rotationConfig struct {
+    maxSize int64
+    interval time.Duration
+    maxBackups int
+    compress bool
+    clock Clock
+}
+
+// RotationOption is an option used when creating a new RotatingFileWriter.
+type RotationOption func(*rotationConfig) error
+
+// RotateAtSize rotates the file before a write would take it over maxSize
+// bytes.
+func RotateAtSize(maxSize int64) RotationOption {
+    return func(config *rotationConfig) error {
+        if maxSize <= 0 {
+            return errors.Errorf("Rotation size must be positive: %d", maxSize)
+        }
+        config.maxSize = maxSize
+
+        return nil
+    }
+}
+
+// RotateEvery rotates the file on the first write after each multiple of
+// interval (since the zero time, so a 24 hour interval rotates at midnight
+// UTC).
+func RotateEvery(interval time.Duration) RotationOption {
+    return func(config *rotationConfig) error {
+        if interval <= 0 {
+            return errors.Errorf(
+                "Rotation interval must be positive: %s", interval,
+            )
+        }
+        config.interval = interval
+
+        return nil
+    }
+}
+
+// KeepBackups sets the number of rotated files to keep; older ones are
+// removed. By default every rotated file is kept.
+func KeepBackups(maxBackups int) RotationOption {
+    return func(config *rotationConfig) error {
+        if maxBackups < 0 {
+            return errors.Errorf(
+                "Number of backups cannot be negative: %d", maxBackups,
+            )
+        }
+        config.maxBackups = maxBackups
+
+        return nil
+    }
+}
+
+// CompressBackups gzips rotated files in the background.
+func CompressBackups() RotationOption {
+    return func(config *rotationConfig) error {
+        config.compress = true
+
+        return nil
+    }
+}
+
+// RotationClock sets the Clock used to decide when to rotate and to name
+// rotated files. The global Clock is used by default.
+func RotationClock(clock Clock) RotationOption {
+    return func(config *rotationConfig) error {
+        if clock == nil {
+            return errors.New("Clock cannot be nil")
+        }
+        config.clock = clock
+
+        return nil
+    }
+}
+
+// RotatingFileWriter is a writer for a log file which rotates the file by
+// size and/or time. Rotated files are renamed with the time they were rotated
+// added before their extension:
+//   app.log -> app-2019-03-09T14-59-50.000.log
+type RotatingFileWriter struct {
+    filename string
+    config rotationConfig
+
+    mutex sync.Mutex
+    file *os.File
+    size int64
+    nextRotation time.Time
+    closed bool
+
+    // backupsMutex serialises compressing & removing backups which happens
+    // in the background.
+    backupsMutex sync.Mutex
+    backupsWaitGroup sync.WaitGroup
+}
+
+// NewRotatingFileWriter opens (or creates) filename for appending and returns
+// a RotatingFileWriter for it.
+func NewRotatingFileWriter(
+    filename string, options ...RotationOption,
+) (*RotatingFileWriter, error) {
+    config := rotationConfig{}
+    for i, opt := range options {
+        err := opt(&config)
+        if err != nil {
+            return nil, errors.Wrapf(
+                err, "Error while processing option #%d", i,
+            )
+        }
+    }
+
+    writer := &RotatingFileWriter{
+        filename: filename,
+        config: config,
+    }
+    err := writer.open()
+    if err != nil {
+        return nil, err
+    }
+
+    return writer, nil
+}
+
+func (self *RotatingFileWriter) now() time.Time {
+    if self.config.clock != nil {
+        return self.config.clock.Now()
+    }
+
+    return GetGlobalClock().Now()
+}
+
+// open opens the file, creating it and its directory if needed.
+func (self *RotatingFileWriter) open() error {
+    err := os.MkdirAll(filepath.Dir(self.filename), 0755)
+    if err != nil {
+        return errors.Wrapf(
+            err, "Error while creating directory for '%s'", self.filename,
+        )
+    }
+
+    file, err := os.OpenFile(
+        self.filename, os.O_CREATE | os.O_APPEND | os.O_WRONLY, 0644,
+    )
+    if err != nil {
+        return errors.Wrapf(err, "Error while opening '%s'", self.filename)
+    }
+    info, err := file.Stat()
+    if err != nil {
+        _ = file.Close()
+        return errors.Wrapf(err, "Error while opening '%s'", self.filename)
+    }
+
+    self.file = file
+    self.size = info.Size()
+    if self.config.interval > 0 {
+        self.nextRotation = self.now().Truncate(
+            self.config.interval,
+        ).Add(self.config.interval)
+    }
+
+    return nil
+}
+
+func (self *RotatingFileWriter) shouldRotate(writeSize int) bool {
+    if self.config.maxSize > 0 && self.size > 0 &&
+        self.size + int64(writeSize) > self.config.maxSize {
+        return true
+    }
+
+    return self.config.interval > 0 && !self.now().Before(self.nextRotation)
+}
+
+// Write writes p to the file, rotating it first if needed.
+func (self *RotatingFileWriter) Write(p []byte) (int, error) {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+
+    if self.closed {
+        return 0, ErrRotatingFileWriterClosed
+    }
+
+    if self.file == nil || self.shouldRotate(len(p)) {
+        err := self.rotate()
+        if err != nil {
+            return 0, err
+        }
+    }
+
+    n, err := self.file.Write(p)
+    self.size += int64(n)
+
+    return n, err
+}
+
+// Rotate rotates the file immediately.
+func (self *RotatingFileWriter) Rotate() error {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+
+    if self.closed {
+        return ErrRotatingFileWriterClosed
+    }
+
+    return self.rotate()
+}
+
+// rotate renames the current file to a backup and opens a new one. If the
+// file couldn't be opened previously it is just opened again.
+func (self *RotatingFileWriter) rotate() error {
+    if self.file != nil {
+        err := self.file.Close()
+        self.file = nil
+        if err != nil {
+            return errors.Wrapf(err, "Error while closing '%s'", self.filename)
+        }
+
+        err = os.Rename(self.filename, self.backupName())
+        if err != nil && !os.IsNotExist(err) {
+            return errors.Wrapf(
+                err, "Error while rotating '%s'", self.filename,
+            )
+        }
+    }
+
+    err := self.open()
+    if err != nil {
+        return err
+    }
+
+    if self.config.compress || self.config.maxBackups > 0 {
+        self.backupsWaitGroup.Add(1)
+        go self.processBackups()
+    }
+
+    return nil
+}
+
+// Reopen closes and reopens the file without rotating it. This is intended
+// for when the file has been moved by an external tool such as logrotate
+// (usually in response to a SIGHUP).
+func (self *RotatingFileWriter) Reopen() error {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+
+    if self.closed {
+        return ErrRotatingFileWriterClosed
+    }
+
+    if self.file != nil {
+        err := self.file.Close()
+        self.file = nil
+        if err != nil {
+            return errors.Wrapf(err, "Error while closing '%s'", self.filename)
+        }
+    }
+
+    return self.open()
+}
+
+// Sync commits the file's contents to disk.
+func (self *RotatingFileWriter) Sync() error {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+
+    if self.file == nil {
+        return nil
+    }
+
+    return self.file.Sync()
+}
+
+// Close closes the file and waits for any backups to finish being compressed
+// or removed.
+func (self *RotatingFileWriter) Close() error {
+    self.mutex.Lock()
+    var err error
+    if !self.closed {
+        self.closed = true
+        if self.file != nil {
+            err = self.file.Close()
+            self.file = nil
+        }
+    }
+    self.mutex.Unlock()
+
+    self.backupsWaitGroup.Wait()
+
+    return err
+}
+
+// backupParts splits the filename into the prefix & extension used for the
+// names of backups.
+func (self *RotatingFileWriter) backupParts() (string, string) {
+    extension := filepath.Ext(self.filename)
+    prefix := strings.TrimSuffix(self.filename, extension) + "-"
+
+    return prefix, extension
+}
+
+func (self *RotatingFileWriter) backupName() string {
+    prefix, extension := self.backupParts()
+    name := prefix + self.now().Format(backupTimeLayout) + extension
+
+    // NOTE: Multiple rotations in the same millisecond get a counter so they
+    //       don't overwrite each other.
+    candidate := name
+    for i := 1; backupExists(candidate); i++ {
+        candidate = fmt.Sprintf("%s.%d", name, i)
+    }
+
+    return candidate
+}
+
+func backupExists(name string) bool {
+    for _, candidate := range []string{name, name + compressedSuffix} {
+        if _, err := os.Stat(candidate); err == nil {
+            return true
+        }
+    }
+
+    return false
+}
+
+// backups gets the names of all the backups for the file, oldest first.
+func (self *RotatingFileWriter) backups() ([]string, error) {
+    prefix, _ := self.backupParts()
+    matches, err := filepath.Glob(globEscape(prefix) + "*")
+    if err != nil {
+        return nil, err
+    }
+
+    var backups []string
+    for _, match := range matches {
+        timestamp := strings.TrimPrefix(match, prefix)
+        if len(timestamp) < len(backupTimeLayout) {
+            continue
+        }
+        _, err := time.Parse(
+            backupTimeLayout, timestamp[:len(backupTimeLayout)],
+        )
+        if err == nil {
+            backups = append(backups, match)
+        }
+    }
+    sort.Strings(backups)
+
+    return backups, nil
+}
+
+func globEscape(pattern string) string {
+    replacer := strings.NewReplacer(
+        `*`, `\*`, `?`, `\?`, `[`, `\[`, `\`, `\\`,
+    )
+
+    return replacer.Replace(pattern)
+}
+
+// processBackups compresses any uncompressed backups and removes the oldest
+// backups beyond the number to keep.
+func (self *RotatingFileWriter) processBackups() {
+    defer self.backupsWaitGroup.Done()
+
+    self.backupsMutex.Lock()
+    defer self.backupsMutex.Unlock()
+
+    backups, err := self.backups()
+    if err != nil {
+        return
+    }
+
+    if self.config.maxBackups > 0 && len(backups) > self.config.maxBackups {
+        excess := len(backups) - self.config.maxBackups
+        for _, backup := range backups[:excess] {
+            _ = os.Remove(backup)
+        }
+        backups = backups[excess:]
+    }
+
+    if self.config.compress {
+        for _, backup := range backups {
+            if !strings.HasSuffix(backup, compressedSuffix) {
+                _ = compressFile(backup)
+            }
+        }
+    }
+}
+
+// compressFile gzips the file to a new file with the compressed suffix and
+// then removes the original.
+func compressFile(name string) (err error) {
+    source, err := os.Open(name)
+    if err != nil {
+        return err
+    }
+    defer source.Close()
+
+    compressedName := name + compressedSuffix
+    destination, err := os.OpenFile(
+        compressedName, os.O_CREATE | os.O_TRUNC | os.O_WRONLY, 0644,
+    )
+    if err != nil {
+        return err
+    }
+    defer func() {
+        if err != nil {
+            _ = os.Remove(compressedName)
+        }
+    }()
+
+    gzipWriter := gzip.NewWriter(destination)
+    _, err = io.Copy(gzipWriter, source)
+    if err == nil {
+        err = gzipWriter.Close()
+    }
+    if closeErr := destination.Close(); err == nil {
+        err = closeErr
+    }
+    if err != nil {
+        return err
+    }
+
+    return os.Remove(name)
+}
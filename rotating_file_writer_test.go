@@ -0,0 +1,176 @@
+package logging
+
+import (
+    "compress/gzip"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "sort"
+    "testing"
+    "time"
+
+    gm "github.com/onsi/gomega"
+)
+
+func readDir(g *gm.GomegaWithT, dir string) map[string]string {
+    entries, err := ioutil.ReadDir(dir)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    contents := make(map[string]string)
+    for _, entry := range entries {
+        data, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+        g.Expect(err).ToNot(gm.HaveOccurred())
+        contents[entry.Name()] = string(data)
+    }
+
+    return contents
+}
+
+func sortedKeys(contents map[string]string) []string {
+    keys := make([]string, 0, len(contents))
+    for key := range contents {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+
+    return keys
+}
+
+func TestRotatingFileWriterSize(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    dir := t.TempDir()
+    clock := NewFixedClock(time.Date(2019, 3, 9, 14, 59, 50, 0, time.UTC))
+    writer, err := NewRotatingFileWriter(
+        filepath.Join(dir, "app.log"),
+        RotateAtSize(8),
+        RotationClock(clock),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    for _, line := range []string{"one\n", "two\n", "three\n"} {
+        _, err = writer.Write([]byte(line))
+        g.Expect(err).ToNot(gm.HaveOccurred())
+        clock.Advance(time.Second)
+    }
+    g.Expect(writer.Close()).To(gm.Succeed())
+
+    g.Expect(readDir(g, dir)).To(gm.Equal(map[string]string{
+        "app-2019-03-09T14-59-52.000.log": "one\ntwo\n",
+        "app.log": "three\n",
+    }))
+
+    _, err = writer.Write([]byte("four\n"))
+    g.Expect(err).To(gm.Equal(ErrRotatingFileWriterClosed))
+}
+
+func TestRotatingFileWriterInterval(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    dir := t.TempDir()
+    clock := NewFixedClock(time.Date(2019, 3, 9, 14, 59, 50, 0, time.UTC))
+    writer, err := NewRotatingFileWriter(
+        filepath.Join(dir, "app.log"),
+        RotateEvery(time.Hour),
+        RotationClock(clock),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer writer.Close()
+
+    _, err = writer.Write([]byte("one\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    clock.Advance(5 * time.Second)
+    _, err = writer.Write([]byte("two\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    clock.Advance(time.Minute)
+    _, err = writer.Write([]byte("three\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    g.Expect(readDir(g, dir)).To(gm.Equal(map[string]string{
+        "app-2019-03-09T15-00-55.000.log": "one\ntwo\n",
+        "app.log": "three\n",
+    }))
+}
+
+func TestRotatingFileWriterBackups(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    dir := t.TempDir()
+    clock := NewFixedClock(time.Date(2019, 3, 9, 14, 59, 50, 0, time.UTC))
+    writer, err := NewRotatingFileWriter(
+        filepath.Join(dir, "app.log"),
+        KeepBackups(2),
+        CompressBackups(),
+        RotationClock(clock),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    for _, line := range []string{"one\n", "two\n", "three\n", "four\n"} {
+        _, err = writer.Write([]byte(line))
+        g.Expect(err).ToNot(gm.HaveOccurred())
+        clock.Advance(time.Second)
+        g.Expect(writer.Rotate()).To(gm.Succeed())
+    }
+    g.Expect(writer.Close()).To(gm.Succeed())
+
+    contents := readDir(g, dir)
+    g.Expect(sortedKeys(contents)).To(gm.Equal([]string{
+        "app-2019-03-09T14-59-53.000.log.gz",
+        "app-2019-03-09T14-59-54.000.log.gz",
+        "app.log",
+    }))
+
+    compressed, err := os.Open(
+        filepath.Join(dir, "app-2019-03-09T14-59-54.000.log.gz"),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer compressed.Close()
+    gzipReader, err := gzip.NewReader(compressed)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    data, err := ioutil.ReadAll(gzipReader)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(string(data)).To(gm.Equal("four\n"))
+}
+
+func TestRotatingFileWriterReopen(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    dir := t.TempDir()
+    filename := filepath.Join(dir, "app.log")
+    writer, err := NewRotatingFileWriter(filename)
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer writer.Close()
+
+    _, err = writer.Write([]byte("one\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    // NOTE: Simulate logrotate moving the file out of the way.
+    err = os.Rename(filename, filepath.Join(dir, "app.log.1"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    _, err = writer.Write([]byte("two\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    g.Expect(writer.Reopen()).To(gm.Succeed())
+    _, err = writer.Write([]byte("three\n"))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    g.Expect(readDir(g, dir)).To(gm.Equal(map[string]string{
+        "app.log.1": "one\ntwo\n",
+        "app.log": "three\n",
+    }))
+}
+
+func TestRotatingFileWriterOptions(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    filename := filepath.Join(t.TempDir(), "app.log")
+    _, err := NewRotatingFileWriter(filename, RotateAtSize(0))
+    g.Expect(err).To(gm.MatchError(
+        "Error while processing option #0: Rotation size must be " +
+            "positive: 0",
+    ))
+    _, err = NewRotatingFileWriter(filename, KeepBackups(-1))
+    g.Expect(err).To(gm.HaveOccurred())
+    _, err = NewRotatingFileWriter(filename, RotateEvery(0))
+    g.Expect(err).To(gm.HaveOccurred())
+}
@@ -0,0 +1,319 @@
+package logging
+
+import (
+    "sort"
+    "sync"
+    "time"
+
+    "github.com/pkg/errors"
+)
+
+// Keys & message used for the logs reporting how many logs were sampled away.
+const (
+    samplingReportMessage = "Logs were dropped by sampling"
+    sampledMessageKey = "sampled_message"
+    sampledCountKey = "sampled_count"
+)
+
+// sampleRule is how many logs with the same level & message are logged each
+// interval.
+type sampleRule struct {
+    first int
+    thereafter int
+}
+
+// allows decides whether the count-th log (starting at 1) in an interval is
+// logged.
+func (self sampleRule) allows(count int) bool {
+    if count <= self.first {
+        return true
+    }
+
+    return self.thereafter > 0 && (count - self.first) % self.thereafter == 0
+}
+
+type samplingPolicy struct {
+    interval time.Duration
+    rule sampleRule
+    // levels overrides rule for specific levels; a nil rule means the level
+    // is never sampled.
+    levels map[LogLevel]*sampleRule
+}
+
+// SamplingOption is an option used to configure sampling with WithSampling.
+type SamplingOption func(*samplingPolicy) error
+
+func newSampleRule(first, thereafter int) (*sampleRule, error) {
+    if first < 0 {
+        return nil, errors.Errorf(
+            "Number of logs before sampling cannot be negative: %d", first,
+        )
+    }
+    if thereafter < 0 {
+        return nil, errors.Errorf(
+            "Sampling rate cannot be negative: %d", thereafter,
+        )
+    }
+
+    return &sampleRule{first: first, thereafter: thereafter}, nil
+}
+
+// SampleLevel overrides how logs at level are sampled; see WithSampling for
+// what first & thereafter mean. FATAL and PANIC logs can't be sampled.
+func SampleLevel(level LogLevel, first, thereafter int) SamplingOption {
+    return func(policy *samplingPolicy) error {
+        if _, err := severityForLevel(level); err != nil {
+            return err
+        }
+        if level == FATAL || level == PANIC {
+            return errors.Errorf("Logs at level '%s' cannot be sampled", level)
+        }
+        rule, err := newSampleRule(first, thereafter)
+        if err != nil {
+            return errors.Wrapf(
+                err, "Error while setting sampling for level '%s'", level,
+            )
+        }
+        policy.levels[level] = rule
+
+        return nil
+    }
+}
+
+// NeverSample logs everything at the provided levels; for example to make
+// sure no errors are lost:
+//   logging.NeverSample(logging.ERROR)
+func NeverSample(levels ...LogLevel) SamplingOption {
+    return func(policy *samplingPolicy) error {
+        for _, level := range levels {
+            if _, err := severityForLevel(level); err != nil {
+                return err
+            }
+            policy.levels[level] = nil
+        }
+
+        return nil
+    }
+}
+
+// WithSampling caps the number of logs with the same level & message the new
+// Logger makes. In each interval the first logs are logged and after that
+// only every thereafter-th log is (a thereafter of 0 drops them all).
+//
+// The number of logs dropped for each level & message is reported in a log at
+// the same level once an interval has passed (even if nothing else is logged)
+// and when the Logger is flushed or closed. FATAL and PANIC logs are never
+// sampled.
+//
+// Child loggers made with With share the new Logger's counts while loggers
+// cloned from it get their own.
+func WithSampling(
+    first, thereafter int, interval time.Duration, options ...SamplingOption,
+) LoggerOption {
+    return func(loggerConfig *loggerConfig) error {
+        if interval <= 0 {
+            return errors.Errorf(
+                "Sampling interval must be positive: %s", interval,
+            )
+        }
+        rule, err := newSampleRule(first, thereafter)
+        if err != nil {
+            return err
+        }
+
+        policy := &samplingPolicy{
+            interval: interval,
+            rule: *rule,
+            levels: map[LogLevel]*sampleRule{
+                FATAL: nil,
+                PANIC: nil,
+            },
+        }
+        for i, opt := range options {
+            err := opt(policy)
+            if err != nil {
+                return errors.Wrapf(
+                    err, "Error while processing sampling option #%d", i,
+                )
+            }
+        }
+        loggerConfig.samplingPolicy = policy
+
+        return nil
+    }
+}
+
+type sampleKey struct {
+    level LogLevel
+    message string
+}
+
+type sampleCounter struct {
+    intervalEnd time.Time
+    count int
+    dropped int
+}
+
+// sampleReport is the number of logs dropped for a level & message.
+type sampleReport struct {
+    sampleKey
+    dropped int
+}
+
+// sampler counts the logs made with each level & message to decide which are
+// sampled away.
+type sampler struct {
+    policy *samplingPolicy
+
+    mutex sync.Mutex
+    counters map[sampleKey]*sampleCounter
+    // nextSweep is when counters will next be checked for intervals which
+    // are over so that they don't build up.
+    nextSweep time.Time
+    // timer reports the dropped logs once an interval has passed even if no
+    // more logs are made; it's only set while there are dropped logs.
+    timer *time.Timer
+    // timerGeneration identifies the current timer so one which has been
+    // stopped can't report drops which have already been reported.
+    timerGeneration int
+}
+
+func newSampler(policy *samplingPolicy) *sampler {
+    return &sampler{
+        policy: policy,
+        counters: make(map[sampleKey]*sampleCounter),
+    }
+}
+
+// sample decides whether a log made by logger should be logged and gets the
+// reports for any intervals which are over.
+func (self *sampler) sample(
+    logger *Logger, level LogLevel, message string, now time.Time,
+) (bool, []sampleReport) {
+    rule, ok := self.policy.levels[level]
+    if !ok {
+        rule = &self.policy.rule
+    }
+
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+
+    var reports []sampleReport
+    if !now.Before(self.nextSweep) {
+        for key, counter := range self.counters {
+            if !now.Before(counter.intervalEnd) {
+                reports = appendSampleReport(reports, key, counter)
+                delete(self.counters, key)
+            }
+        }
+        self.nextSweep = now.Add(self.policy.interval)
+        sortSampleReports(reports)
+    }
+
+    if rule == nil {
+        return true, reports
+    }
+
+    key := sampleKey{level: level, message: message}
+    counter, ok := self.counters[key]
+    if ok && !now.Before(counter.intervalEnd) {
+        reports = appendSampleReport(reports, key, counter)
+        ok = false
+    }
+    if !ok {
+        counter = &sampleCounter{intervalEnd: now.Add(self.policy.interval)}
+        self.counters[key] = counter
+    }
+
+    counter.count++
+    if rule.allows(counter.count) {
+        return true, reports
+    }
+    counter.dropped++
+    if self.timer == nil {
+        self.timerGeneration++
+        generation := self.timerGeneration
+        self.timer = time.AfterFunc(self.policy.interval, func() {
+            self.expire(logger, generation)
+        })
+    }
+
+    return false, reports
+}
+
+// expire writes the reports for the dropped logs when the timer goes off
+// unless they've already been reported.
+func (self *sampler) expire(logger *Logger, generation int) {
+    self.mutex.Lock()
+    if self.timer == nil || self.timerGeneration != generation {
+        self.mutex.Unlock()
+        return
+    }
+    reports := self.reports()
+    self.mutex.Unlock()
+
+    logger.writeSampleReports(logger.loadState(), reports)
+}
+
+// drain gets the reports for every level & message which has had logs
+// dropped and resets their dropped counts.
+func (self *sampler) drain() []sampleReport {
+    self.mutex.Lock()
+    defer self.mutex.Unlock()
+
+    return self.reports()
+}
+
+func (self *sampler) reports() []sampleReport {
+    if self.timer != nil {
+        self.timer.Stop()
+        self.timer = nil
+    }
+
+    var reports []sampleReport
+    for key, counter := range self.counters {
+        reports = appendSampleReport(reports, key, counter)
+        counter.dropped = 0
+    }
+    sortSampleReports(reports)
+
+    return reports
+}
+
+func appendSampleReport(
+    reports []sampleReport, key sampleKey, counter *sampleCounter,
+) []sampleReport {
+    if counter.dropped == 0 {
+        return reports
+    }
+
+    return append(reports, sampleReport{
+        sampleKey: key,
+        dropped: counter.dropped,
+    })
+}
+
+// sortSampleReports sorts reports by message & then level so they're written
+// in a consistent order.
+func sortSampleReports(reports []sampleReport) {
+    sort.Slice(reports, func(i, j int) bool {
+        if reports[i].message != reports[j].message {
+            return reports[i].message < reports[j].message
+        }
+        return reports[i].level < reports[j].level
+    })
+}
+
+func (self *Logger) writeSampleReports(
+    state *loggerState, reports []sampleReport,
+) {
+    for _, report := range reports {
+        fields := []Field{
+            {Key: sampledMessageKey, Value: report.message},
+            {Key: sampledCountKey, Value: report.dropped},
+        }
+        self.writeRecord(nil, state, self.newRecord(
+            state, report.level, samplingReportMessage, fields,
+        ))
+    }
+}
@@ -0,0 +1,154 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "math/rand"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestLoggerSampling(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    clock := NewFixedClock(time.Unix(1552143590, 0))
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(Logfmt),
+        WithClock(clock),
+        WithUTC(true),
+        WithSampling(2, 3, time.Second),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    for i := 0; i < 8; i++ {
+        newLogger.Infow("Foo", "i", i)
+    }
+    newLogger.Warn("Foo")
+
+    g.Expect(builder.String()).To(gm.Equal(
+        "ts=2019-03-09T14:59:50Z level=info msg=Foo i=0\n" +
+            "ts=2019-03-09T14:59:50Z level=info msg=Foo i=1\n" +
+            "ts=2019-03-09T14:59:50Z level=info msg=Foo i=4\n" +
+            "ts=2019-03-09T14:59:50Z level=info msg=Foo i=7\n" +
+            "ts=2019-03-09T14:59:50Z level=warn msg=Foo\n",
+    ))
+
+    // NOTE: The counts reset and are reported once the interval is over.
+    builder.Reset()
+    clock.Advance(time.Second)
+    newLogger.Warn("Bar")
+    newLogger.Info("Foo")
+    g.Expect(builder.String()).To(gm.Equal(
+        "ts=2019-03-09T14:59:51Z level=info " +
+            "msg=\"Logs were dropped by sampling\" sampled_message=Foo sampled_count=4\n" +
+            "ts=2019-03-09T14:59:51Z level=warn msg=Bar\n" +
+            "ts=2019-03-09T14:59:51Z level=info msg=Foo\n",
+    ))
+
+    builder.Reset()
+    newLogger.Info("Foo")
+    newLogger.Info("Foo")
+    newLogger.With(Extras{"a": 1}).Info("Foo")
+    g.Expect(builder.String()).To(gm.Equal(
+        "ts=2019-03-09T14:59:51Z level=info msg=Foo\n",
+    ))
+
+    builder.Reset()
+    g.Expect(newLogger.Flush()).To(gm.Succeed())
+    g.Expect(newLogger.Flush()).To(gm.Succeed())
+    g.Expect(builder.String()).To(gm.Equal(
+        "ts=2019-03-09T14:59:51Z level=info " +
+            "msg=\"Logs were dropped by sampling\" sampled_message=Foo sampled_count=2\n",
+    ))
+}
+
+func TestLoggerSamplingLevels(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(Logfmt),
+        WithLogLevel(DEBUG),
+        WithSampling(
+            1,
+            0,
+            time.Hour,
+            NeverSample(ERROR),
+            SampleLevel(DEBUG, 2, 0),
+        ),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    for i := 0; i < 3; i++ {
+        newLogger.Error("Foo")
+        newLogger.Debug("Bar")
+        newLogger.Info("Baz")
+    }
+    g.Expect(strings.Count(builder.String(), "msg=Foo")).To(gm.Equal(3))
+    g.Expect(strings.Count(builder.String(), "msg=Bar")).To(gm.Equal(2))
+    g.Expect(strings.Count(builder.String(), "msg=Baz")).To(gm.Equal(1))
+
+    builder.Reset()
+    g.Expect(newLogger.Close()).To(gm.Succeed())
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^ts=\S+ level=debug msg="Logs were dropped by sampling" ` +
+            `sampled_message=Bar sampled_count=1\n` +
+            `ts=\S+ level=info msg="Logs were dropped by sampling" ` +
+            `sampled_message=Baz sampled_count=2\n$`,
+    ))
+}
+
+func TestLoggerSamplingReportedAfterInterval(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder lockedBuilder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(Logfmt),
+        WithSampling(1, 0, 20 * time.Millisecond),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    for i := 0; i < 5; i++ {
+        newLogger.Info("Foo")
+    }
+
+    // NOTE: The drops are reported without anything else being logged.
+    g.Eventually(builder.String).Should(gm.MatchRegexp(
+        `^ts=\S+ level=info msg=Foo\n` +
+            `ts=\S+ level=info msg="Logs were dropped by sampling" ` +
+            `sampled_message=Foo sampled_count=4\n$`,
+    ))
+    g.Consistently(builder.String, 100 * time.Millisecond).Should(
+        gm.HaveSuffix("sampled_count=4\n"),
+    )
+}
+
+func TestWithSamplingErrors(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    options := []LoggerOption{
+        WithSampling(1, 1, 0),
+        WithSampling(-1, 1, time.Second),
+        WithSampling(1, -1, time.Second),
+        WithSampling(1, 1, time.Second, NeverSample(LogLevel("NONSENSE"))),
+        WithSampling(1, 1, time.Second, SampleLevel(INFO, -1, 0)),
+        WithSampling(1, 0, time.Hour, SampleLevel(FATAL, 0, 0)),
+        WithSampling(1, 0, time.Hour, SampleLevel(PANIC, 0, 0)),
+    }
+    for _, option := range options {
+        _, err := NewLogger("test" + strconv.Itoa(rand.Int()), option)
+        g.Expect(err).To(gm.HaveOccurred())
+    }
+}
@@ -0,0 +1,162 @@
+package logging
+
+import (
+    "io"
+    "log"
+
+    "github.com/pkg/errors"
+)
+
+// Filter decides whether a Record should be written to a sink.
+type Filter func(record *Record) bool
+
+// sink is a writer along with its configuration. Like loggerState a sink must
+// never be modified once it has been stored.
+type sink struct {
+    logger *log.Logger
+    // minSeverity is nil when the sink uses its Logger's level.
+    minSeverity *int
+    // formatter is nil when the sink uses its Logger's formatter.
+    formatter Formatter
+    // filter is nil when every Record should be written.
+    filter Filter
+    // writerFormatter is the Formatter used for the sink's writer when the
+    // Formatter it uses is a WriterFormatter. It's resolved once whenever the
+    // sinks or formatter of a Logger change rather than for every log.
+    writerFormatter Formatter
+}
+
+// SinkOption is an option used when adding a sink to a Logger.
+type SinkOption func(*sink) error
+
+// SinkFormatter sets the Formatter used for a sink, overriding the format it
+// was given.
+func SinkFormatter(formatter Formatter) SinkOption {
+    return func(sink *sink) error {
+        if formatter == nil {
+            return errors.New("Formatter cannot be nil")
+        }
+        sink.formatter = formatter
+
+        return nil
+    }
+}
+
+// SinkFilter sets a Filter for a sink; only Records the filter returns true
+// for are written to it.
+func SinkFilter(filter Filter) SinkOption {
+    return func(sink *sink) error {
+        sink.filter = filter
+
+        return nil
+    }
+}
+
+// newSink makes a sink for writer. An UnsetLogLevel or UnsetFormat means the
+// Logger's level or format will be used. logger is reused if it isn't nil.
+func newSink(
+    writer io.Writer,
+    logger *log.Logger,
+    logLevel LogLevel,
+    logFormat LogFormat,
+    options []SinkOption,
+) (*sink, error) {
+    if logger == nil {
+        logger = log.New(writer, "", 0)
+    }
+    newSink := &sink{logger: logger}
+
+    if logLevel != UnsetLogLevel {
+        minSeverity, err := severityForLevel(logLevel)
+        if err != nil {
+            return nil, errors.Wrap(err, "Error while setting sink level")
+        }
+        newSink.minSeverity = &minSeverity
+    }
+
+    if logFormat != UnsetFormat {
+        newSink.formatter = GetFormatter(logFormat)
+        if newSink.formatter == nil {
+            return nil, errors.Errorf("Unknown log format '%d'", logFormat)
+        }
+    }
+
+    for i, opt := range options {
+        err := opt(newSink)
+        if err != nil {
+            return nil, errors.Wrapf(
+                err, "Error while processing sink option #%d", i,
+            )
+        }
+    }
+
+    return newSink, nil
+}
+
+// resolveWriterFormatters resolves the writerFormatter of each sink in state
+// replacing the sinks which need it changed.
+func (self *loggerState) resolveWriterFormatters() {
+    var newSinks map[io.Writer]*sink
+    for writer, writerSink := range self.sinks {
+        formatter := writerSink.formatter
+        if formatter == nil {
+            formatter = self.formatter
+        }
+        var writerFormatter Formatter
+        if resolver, ok := formatter.(WriterFormatter); ok {
+            writerFormatter = resolver.ForWriter(writer)
+        }
+        if writerFormatter == nil && writerSink.writerFormatter == nil {
+            continue
+        }
+
+        if newSinks == nil {
+            newSinks = make(map[io.Writer]*sink, len(self.sinks))
+            for writer, writerSink := range self.sinks {
+                newSinks[writer] = writerSink
+            }
+        }
+        resolvedSink := *writerSink
+        resolvedSink.writerFormatter = writerFormatter
+        newSinks[writer] = &resolvedSink
+    }
+
+    if newSinks != nil {
+        self.sinks = newSinks
+    }
+}
+
+// addSinks makes a new map containing the sinks in base and default sinks
+// for any of the provided writers which aren't already in base. The
+// log.Loggers from existing are reused so a writer never has two log.Loggers
+// writing to it at once.
+func addSinks(
+    base map[io.Writer]*sink,
+    writers []io.Writer,
+    existing map[io.Writer]*sink,
+) map[io.Writer]*sink {
+    newSinks := make(map[io.Writer]*sink)
+    for writer, writerSink := range base {
+        newSinks[writer] = writerSink
+    }
+    for _, writer := range writers {
+        if _, ok := newSinks[writer]; ok {
+            continue
+        }
+        newSinks[writer] = &sink{logger: existingLogger(writer, existing)}
+    }
+
+    return newSinks
+}
+
+// existingLogger gets the log.Logger for writer from existing or a new one if
+// there isn't one.
+func existingLogger(
+    writer io.Writer, existing map[io.Writer]*sink,
+) *log.Logger {
+    if existingSink, ok := existing[writer]; ok {
+        return existingSink.logger
+    }
+
+    return log.New(writer, "", 0)
+}
@@ -0,0 +1,108 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "math/rand"
+    "strconv"
+    "strings"
+    "testing"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestLoggerSinks(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var debugBuilder, warnBuilder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogLevel(INFO),
+        WithFormat(JSON),
+        WithSink(&debugBuilder, DEBUG, Logfmt),
+        WithSink(&warnBuilder, WARN, UnsetFormat),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Debug("Foo")
+    newLogger.Warn("Bar")
+
+    g.Expect(debugBuilder.String()).To(gm.MatchRegexp(
+        `^ts=\S+ level=debug msg=Foo\nts=\S+ level=warn msg=Bar\n$`,
+    ))
+    g.Expect(warnBuilder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"WARN","message":"Bar"}\n$`,
+    ))
+}
+
+func TestLoggerAddSink(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder, sinkBuilder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    err = newLogger.AddSink(
+        &sinkBuilder,
+        UnsetLogLevel,
+        Standard,
+        SinkFilter(func(record *Record) bool {
+            return record.Message != "Secret"
+        }),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    newLogger.Info("Foo")
+    newLogger.Info("Secret")
+    newLogger.Debug("Bar")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"INFO","message":"Foo"}\n` +
+            `{"timestamp":\d+,"log_level":"INFO","message":"Secret"}\n$`,
+    ))
+    g.Expect(sinkBuilder.String()).To(gm.MatchRegexp(
+        `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2} INFO Foo\n$`,
+    ))
+
+    // NOTE: Adding an existing writer keeps its sink configuration.
+    newLogger.AddWriters(&sinkBuilder)
+    newLogger.Info("Secret")
+    g.Expect(sinkBuilder.String()).ToNot(gm.ContainSubstring("Secret"))
+
+    // NOTE: Setting the writers removes it.
+    newLogger.SetWriters(&sinkBuilder)
+    newLogger.Info("Secret")
+    g.Expect(sinkBuilder.String()).To(gm.MatchRegexp(
+        `{"timestamp":\d+,"log_level":"INFO","message":"Secret"}\n$`,
+    ))
+}
+
+func TestLoggerSinkErrors(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    _, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithSink(&builder, LogLevel("NONSENSE"), UnsetFormat),
+    )
+    g.Expect(err).To(gm.MatchError(
+        "Error while processing option #0: Error while setting sink " +
+            "level: Incorrect LogLevel: 'NONSENSE'",
+    ))
+
+    newLogger, err := NewLogger("test" + strconv.Itoa(rand.Int()))
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    err = newLogger.AddSink(&builder, INFO, LogFormat(9999))
+    g.Expect(err).To(gm.MatchError(
+        "Error while adding sink: Unknown log format '9999'",
+    ))
+    err = newLogger.AddSink(&builder, INFO, JSON, SinkFormatter(nil))
+    g.Expect(err).To(gm.HaveOccurred())
+}
@@ -0,0 +1,245 @@
+package logging
+
+import (
+    "context"
+    "log/slog"
+    "time"
+
+    "github.com/pkg/errors"
+)
+
+// slogGroupSeparator joins slog group names with their attributes' keys.
+const slogGroupSeparator = "."
+
+// slogHandler is a slog.Handler which logs through a Logger.
+type slogHandler struct {
+    logger *Logger
+    // groupPrefix is prepended to the keys of any attributes; it holds the
+    // names of the groups opened with WithGroup.
+    groupPrefix string
+}
+
+// NewSlogHandler makes a slog.Handler which logs through the provided Logger
+// so that logs made with log/slog are output the same way as any other log
+// made with the Logger:
+//   slog.SetDefault(slog.New(logging.NewSlogHandler(logger)))
+//
+// slog levels are mapped to the LogLevel with the same severity (or the
+// closest one below it). Attributes become extras with the names of any
+// groups they're in joined to their key with a ".".
+//
+// Don't use the handler with WithSlogHandlers on the same Logger; the logs
+// would loop forever.
+func NewSlogHandler(logger *Logger) slog.Handler {
+    return &slogHandler{
+        logger: logger,
+    }
+}
+
+// Enabled reports whether the Logger will write logs at the level.
+func (self *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+    return self.logger.loadState().severityEnabled(int(level))
+}
+
+// Handle logs the record through the Logger.
+func (self *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+    state := self.logger.loadState()
+    if !state.severityEnabled(int(record.Level)) {
+        return nil
+    }
+    level := levelForSeverity(int(record.Level))
+
+    extras := make([]Extras, 0, record.NumAttrs())
+    record.Attrs(func(attr slog.Attr) bool {
+        extras = appendSlogAttr(extras, self.groupPrefix, attr)
+        return true
+    })
+    if self.logger.suppressed(state, level, record.Message, extras) {
+        return nil
+    }
+
+    var caller *Caller
+    if state.caller && record.PC != 0 {
+        caller = callerFromPC(record.PC)
+    }
+
+    loggerRecord := self.logger.buildRecord(
+        ctx, state, level, record.Message, extras, caller,
+    )
+    // NOTE: The slog record's time is kept since it may be being forwarded;
+    //       a zero time means it shouldn't be output at all.
+    if record.Time.IsZero() {
+        loggerRecord.FieldNames.Timestamp = OmitField
+    } else if state.utc {
+        loggerRecord.Time = record.Time.UTC()
+    } else {
+        loggerRecord.Time = record.Time
+    }
+    self.logger.writeRecord(ctx, state, loggerRecord)
+
+    return nil
+}
+
+// WithAttrs makes a handler for a child of the Logger with the attributes
+// bound to it.
+func (self *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    var fields []Field
+    for _, attr := range attrs {
+        for _, extras := range appendSlogAttr(nil, self.groupPrefix, attr) {
+            fields = appendExtras(fields, extras)
+        }
+    }
+
+    return &slogHandler{
+        logger: self.logger.child(fields),
+        groupPrefix: self.groupPrefix,
+    }
+}
+
+// WithGroup makes a handler which puts any further attributes in the group.
+func (self *slogHandler) WithGroup(name string) slog.Handler {
+    if name == "" {
+        return self
+    }
+
+    return &slogHandler{
+        logger: self.logger,
+        groupPrefix: self.groupPrefix + name + slogGroupSeparator,
+    }
+}
+
+// appendSlogAttr appends the attribute to extras as its own Extras (so the
+// order of attributes is kept) flattening any groups.
+func appendSlogAttr(extras []Extras, prefix string, attr slog.Attr) []Extras {
+    attr.Value = attr.Value.Resolve()
+    if attr.Equal(slog.Attr{}) {
+        return extras
+    }
+
+    if attr.Value.Kind() != slog.KindGroup {
+        return append(extras, Extra(prefix + attr.Key, attr.Value.Any()))
+    }
+
+    // NOTE: Groups without a key are inlined.
+    groupPrefix := prefix
+    if attr.Key != "" {
+        groupPrefix += attr.Key + slogGroupSeparator
+    }
+    for _, groupAttr := range attr.Value.Group() {
+        extras = appendSlogAttr(extras, groupPrefix, groupAttr)
+    }
+
+    return extras
+}
+
+// levelForSeverity gets the LogLevel with the provided severity. If there
+// isn't one the level with the closest severity below it is used (or the
+// lowest level if there isn't one below it). Built-in levels are preferred
+// over custom levels with the same severity.
+func levelForSeverity(severity int) LogLevel {
+    var (
+        best, lowest LogLevel
+        bestSeverity, lowestSeverity int
+    )
+    for level, levelSeverity := range loadLevelSeverities() {
+        if lowest == UnsetLogLevel || levelSeverity < lowestSeverity ||
+            (levelSeverity == lowestSeverity && preferLevel(level, lowest)) {
+            lowest, lowestSeverity = level, levelSeverity
+        }
+        if levelSeverity > severity {
+            continue
+        }
+        if best == UnsetLogLevel || levelSeverity > bestSeverity ||
+            (levelSeverity == bestSeverity && preferLevel(level, best)) {
+            best, bestSeverity = level, levelSeverity
+        }
+    }
+
+    if best == UnsetLogLevel {
+        return lowest
+    }
+
+    return best
+}
+
+// preferLevel decides between two levels with the same severity so the
+// choice doesn't depend on map ordering.
+func preferLevel(level, other LogLevel) bool {
+    if isBuiltinLevel(level) != isBuiltinLevel(other) {
+        return isBuiltinLevel(level)
+    }
+
+    return level < other
+}
+
+func isBuiltinLevel(level LogLevel) bool {
+    switch level {
+    case TRACE, DEBUG, INFO, WARN, ERROR, FATAL, PANIC:
+        return true
+    default:
+        return false
+    }
+}
+
+// handleSlog passes the record to each of the slog Handlers which are enabled
+// for its severity.
+func (self *loggerState) handleSlog(
+    ctx context.Context, record *Record, severity int,
+) {
+    if ctx == nil {
+        ctx = context.Background()
+    }
+
+    level := slog.Level(severity)
+    var pc uintptr
+    if record.Caller != nil {
+        pc = record.Caller.pc
+    }
+
+    recordTime := record.Time
+    if record.FieldNames.Timestamp == OmitField {
+        recordTime = time.Time{}
+    }
+
+    slogRecord := slog.NewRecord(recordTime, level, record.Message, pc)
+    for _, field := range record.Fields {
+        slogRecord.AddAttrs(slog.Any(field.Key, field.Value))
+    }
+
+    for _, handler := range self.slogHandlers {
+        if handler.Enabled(ctx, level) {
+            _ = handler.Handle(ctx, slogRecord)
+        }
+    }
+}
+
+// WithSlogHandlers makes the new Logger pass every log it makes to the
+// provided slog.Handlers (along with writing it to any writers) so that it
+// can be output through an existing log/slog configuration. The Logger's
+// level still applies; LogLevels are mapped to the slog.Level with the same
+// severity.
+func WithSlogHandlers(
+    handler slog.Handler, handlers ...slog.Handler,
+) LoggerOption {
+    allHandlers := append([]slog.Handler{handler}, handlers...)
+    return func(loggerConfig *loggerConfig) error {
+        for i, handler := range allHandlers {
+            if handler == nil {
+                return errors.Errorf("Handler #%d cannot be nil", i)
+            }
+        }
+        loggerConfig.slogHandlers = append(
+            loggerConfig.slogHandlers, allHandlers...,
+        )
+
+        return nil
+    }
+}
+
+// SetSlogHandlers sets (overriding) the slog.Handlers this logger passes
+// every log to. Passing no handlers stops the logger using any.
+func (self *Logger) SetSlogHandlers(handlers ...slog.Handler) {
+    self.updateState(func(state *loggerState) {
+        state.slogHandlers = handlers
+    })
+}
@@ -0,0 +1,279 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "context"
+    "encoding/json"
+    "io"
+    "log/slog"
+    "math/rand"
+    "strconv"
+    "strings"
+    "testing"
+    "testing/slogtest"
+    "time"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestSlogHandler(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithLogLevel(DEBUG),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    slogger := slog.New(NewSlogHandler(newLogger))
+    slogger.Debug("Foo", "a", 1, slog.Group("g", "b", "c"))
+    newLogger.Debugw("Foo", "a", 1, "g.b", "c")
+
+    lines := strings.Split(strings.TrimSpace(builder.String()), "\n")
+    g.Expect(lines).To(gm.HaveLen(2))
+    g.Expect(lines[0]).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"DEBUG","message":"Foo",` +
+            `"a":1,"g\.b":"c"}$`,
+    ))
+    g.Expect(lines[0][strings.Index(lines[0], `,"log_level"`):]).To(gm.Equal(
+        lines[1][strings.Index(lines[1], `,"log_level"`):],
+    ))
+
+    builder.Reset()
+    slogger.Log(context.Background(), slog.LevelDebug - 4, "Bar")
+    slogger.Log(context.Background(), slog.LevelWarn + 1, "Baz")
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"WARN","message":"Baz"}\n$`,
+    ))
+    g.Expect(slogger.Enabled(context.Background(), slog.LevelDebug)).To(
+        gm.BeTrue(),
+    )
+    g.Expect(slogger.Enabled(context.Background(), slog.LevelDebug - 1)).To(
+        gm.BeFalse(),
+    )
+}
+
+func TestSlogHandlerConformance(t *testing.T) {
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithFieldNames(FieldNames{
+            Timestamp: slog.TimeKey,
+            Level: slog.LevelKey,
+            Message: slog.MessageKey,
+        }),
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer newLogger.Close()
+
+    results := func() []map[string]interface{} {
+        var logs []map[string]interface{}
+        for _, line := range strings.Split(builder.String(), "\n") {
+            if line == "" {
+                continue
+            }
+            var log map[string]interface{}
+            if err := json.Unmarshal([]byte(line), &log); err != nil {
+                t.Fatal(err)
+            }
+            logs = append(logs, nestSlogGroups(log))
+        }
+        return logs
+    }
+
+    err = slogtest.TestHandler(NewSlogHandler(newLogger), results)
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+// nestSlogGroups turns the dotted keys of flattened slog groups back into
+// nested maps.
+func nestSlogGroups(log map[string]interface{}) map[string]interface{} {
+    nested := make(map[string]interface{})
+    for key, value := range log {
+        parts := strings.Split(key, slogGroupSeparator)
+        group := nested
+        for _, part := range parts[:len(parts) - 1] {
+            subGroup, ok := group[part].(map[string]interface{})
+            if !ok {
+                subGroup = make(map[string]interface{})
+                group[part] = subGroup
+            }
+            group = subGroup
+        }
+        group[parts[len(parts) - 1]] = value
+    }
+
+    return nested
+}
+
+func TestSlogHandlerTime(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithClock(NewFixedClock(time.Unix(1552143590, 0))),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    handler := NewSlogHandler(newLogger)
+    record := slog.NewRecord(time.Unix(1000, 0), slog.LevelInfo, "Foo", 0)
+    g.Expect(handler.Handle(context.Background(), record)).To(gm.Succeed())
+    record = slog.NewRecord(time.Time{}, slog.LevelInfo, "Bar", 0)
+    g.Expect(handler.Handle(context.Background(), record)).To(gm.Succeed())
+
+    g.Expect(builder.String()).To(gm.Equal(
+        `{"timestamp":1000,"log_level":"INFO","message":"Foo"}` + "\n" +
+            `{"log_level":"INFO","message":"Bar"}` + "\n",
+    ))
+}
+
+func TestSlogHandlerWithAttrsAndGroup(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    slogger := slog.New(NewSlogHandler(newLogger)).With(
+        "a", 1,
+    ).WithGroup("request").With("id", "x").WithGroup("")
+    slogger.Info("Foo", "b", 2, slog.Group("", "c", 3), slog.Group("empty"))
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"INFO","message":"Foo","a":1,` +
+            `"request\.id":"x","request\.b":2,"request\.c":3}\n$`,
+    ))
+
+    // NOTE: Attributes bound to the handler don't affect the Logger.
+    builder.Reset()
+    newLogger.Info("Bar")
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"INFO","message":"Bar"}\n$`,
+    ))
+}
+
+func TestSlogHandlerCaller(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithCaller(),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    slog.New(NewSlogHandler(newLogger)).Info("Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `"caller":{"file":"[^"]+/slog_test\.go","line":\d+,` +
+            `"function":"[^"]+\.TestSlogHandlerCaller"}`,
+    ))
+}
+
+func TestLevelForSeverity(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    _, err := RegisterLogLevel(
+        LogLevel("custom" + strconv.Itoa(rand.Int())), 6,
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+
+    g.Expect(levelForSeverity(0)).To(gm.Equal(INFO))
+    g.Expect(levelForSeverity(1)).To(gm.Equal(INFO))
+    g.Expect(levelForSeverity(5)).To(gm.Equal(WARN))
+    severity, _ := levelForSeverity(7).Severity()
+    g.Expect(severity).To(gm.Equal(6))
+    g.Expect(levelForSeverity(12)).To(gm.Equal(FATAL))
+    g.Expect(levelForSeverity(100)).To(gm.Equal(PANIC))
+    g.Expect(levelForSeverity(-100)).To(gm.Equal(TRACE))
+}
+
+func TestLoggerSlogHandlers(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder, slogBuilder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithCaller(),
+        WithSlogHandlers(slog.NewJSONHandler(
+            &slogBuilder,
+            &slog.HandlerOptions{AddSource: true, Level: slog.LevelWarn},
+        )),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo", Extras{"a": 1})
+    newLogger.With(Extras{"b": "c"}).Warn("Bar", Extras{"a": 1})
+
+    g.Expect(builder.String()).To(gm.ContainSubstring(`"message":"Foo"`))
+    g.Expect(builder.String()).To(gm.ContainSubstring(`"message":"Bar"`))
+    g.Expect(slogBuilder.String()).To(gm.MatchRegexp(
+        `^{"time":"[^"]+","level":"WARN","source":{"function":` +
+            `"[^"]+\.TestLoggerSlogHandlers","file":"[^"]+/slog_test\.go",` +
+            `"line":\d+},"msg":"Bar","b":"c","a":1}\n$`,
+    ))
+
+    // NOTE: The Logger's level applies before the handler's.
+    builder.Reset()
+    slogBuilder.Reset()
+    newLogger.SetSlogHandlers(slog.NewTextHandler(&slogBuilder, nil))
+    newLogger.Debug("Baz")
+    newLogger.Info("Qux")
+    g.Expect(builder.String()).ToNot(gm.ContainSubstring("Baz"))
+    g.Expect(slogBuilder.String()).To(gm.MatchRegexp(
+        `^time=\S+ level=INFO msg=Qux\n$`,
+    ))
+
+    newLogger.SetSlogHandlers()
+    newLogger.Info("Quux")
+    g.Expect(slogBuilder.String()).ToNot(gm.ContainSubstring("Quux"))
+}
+
+func TestWithSlogHandlers(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var slogBuilder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(io.Discard),
+        WithSlogHandlers(slog.NewTextHandler(&slogBuilder, nil)),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Infow("Foo", "a", 1)
+
+    g.Expect(slogBuilder.String()).To(gm.MatchRegexp(
+        `^time=\S+ level=INFO msg=Foo a=1\n$`,
+    ))
+
+    _, err = NewLogger(
+        "test" + strconv.Itoa(rand.Int()), WithSlogHandlers(nil),
+    )
+    g.Expect(err).To(gm.HaveOccurred())
+}
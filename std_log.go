@@ -0,0 +1,115 @@
+package logging
+
+import (
+    "log"
+    "strings"
+)
+
+// stdLogWriter is the writer used to send the output of a standard library
+// *log.Logger to a Logger.
+type stdLogWriter struct {
+    logger *Logger
+    logLevel LogLevel
+}
+
+// Write logs p as a single message. Trailing newlines are removed and a
+// level prefix (such as "[WARN] " or "error: ") is used as the level for the
+// log instead of the default.
+func (self *stdLogWriter) Write(p []byte) (int, error) {
+    message := strings.TrimRight(string(p), "\r\n")
+    level, message := parseLevelPrefix(message, self.logLevel)
+
+    // NOTE: The *log.Logger's output method and the method the user called
+    //       on it (Printf etc.) are skipped so the caller is the user's code.
+    self.logger.logToLevel(2, level, message, nil)
+    if level == FATAL || level == PANIC {
+        // NOTE: log.Fatal exits and log.Panic panics straight after writing.
+        _ = self.logger.loadState().flushWriters()
+    }
+
+    return len(p), nil
+}
+
+// levelPrefixAliases are prefixes commonly used for a level which aren't the
+// level's name.
+var levelPrefixAliases = map[string]LogLevel{
+    "WARNING": WARN,
+    "ERR": ERROR,
+}
+
+// parseLevelPrefix gets the level from the start of the message if it has
+// one in the form "[LEVEL]" or "LEVEL:" (ignoring case) and returns the rest
+// of the message. If it doesn't the default level and the unchanged message
+// are returned.
+func parseLevelPrefix(
+    message string, defaultLevel LogLevel,
+) (LogLevel, string) {
+    var name, rest string
+    if strings.HasPrefix(message, "[") {
+        end := strings.Index(message, "]")
+        if end == -1 {
+            return defaultLevel, message
+        }
+        name, rest = message[1:end], message[end + 1:]
+    } else {
+        end := strings.Index(message, ":")
+        if end == -1 {
+            return defaultLevel, message
+        }
+        name, rest = message[:end], message[end + 1:]
+    }
+
+    level := LogLevelFromString(strings.TrimSpace(name))
+    if alias, ok := levelPrefixAliases[string(level)]; ok {
+        level = alias
+    }
+    if _, ok := level.Severity(); !ok || level == UnsetLogLevel {
+        return defaultLevel, message
+    }
+
+    return level, strings.TrimLeft(rest, " \t")
+}
+
+// NewStdLogger makes a standard library *log.Logger which logs each message
+// written to it with the provided Logger at logLevel (unless the message
+// starts with a level prefix such as "[ERROR]" or "warn:"). This is useful
+// for APIs which need a *log.Logger such as http.Server's ErrorLog.
+//
+// The *log.Logger has no prefix or flags since the Logger adds its own
+// timestamp. Messages logged at FATAL or PANIC flush the Logger's writers
+// since Fatal and Panic exit or panic as soon as they've written.
+func NewStdLogger(logger *Logger, logLevel LogLevel) *log.Logger {
+    return log.New(newStdLogWriter(logger, logLevel), "", 0)
+}
+
+// RedirectStdLog sends everything logged with the standard library's log
+// package to the provided Logger in the same way as NewStdLogger. The log
+// package's prefix and flags are cleared so they don't end up in the
+// messages.
+//
+// The returned function restores the log package's previous output, prefix
+// and flags:
+//   restore := logging.RedirectStdLog(logger, logging.INFO)
+//   defer restore()
+func RedirectStdLog(logger *Logger, logLevel LogLevel) func() {
+    previousWriter := log.Writer()
+    previousPrefix := log.Prefix()
+    previousFlags := log.Flags()
+
+    log.SetOutput(newStdLogWriter(logger, logLevel))
+    log.SetPrefix("")
+    log.SetFlags(0)
+
+    return func() {
+        log.SetOutput(previousWriter)
+        log.SetPrefix(previousPrefix)
+        log.SetFlags(previousFlags)
+    }
+}
+
+func newStdLogWriter(logger *Logger, logLevel LogLevel) *stdLogWriter {
+    return &stdLogWriter{
+        logger: logger,
+        logLevel: logLevel,
+    }
+}
@@ -0,0 +1,117 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "log"
+    "math/rand"
+    "runtime"
+    "strconv"
+    "strings"
+    "testing"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestNewStdLogger(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithCaller(),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    stdLogger := NewStdLogger(newLogger, WARN)
+    _, _, line, _ := runtime.Caller(0)
+    stdLogger.Printf("Foo %d", 1)
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"WARN","message":"Foo 1",` +
+            `"caller":{"file":"[^"]+/std_log_test\.go","line":%d,`,
+        line + 1,
+    ))
+
+    builder.Reset()
+    stdLogger.Println("[error] Bar")
+    stdLogger.Print("info: Baz")
+    stdLogger.Print("WARNING:Qux")
+    stdLogger.Print("debug: Hidden")
+    stdLogger.Print("Notalevel: Quux")
+    lines := strings.Split(strings.TrimSpace(builder.String()), "\n")
+    g.Expect(lines).To(gm.HaveLen(4))
+    g.Expect(lines[0]).To(gm.ContainSubstring(
+        `"log_level":"ERROR","message":"Bar"`,
+    ))
+    g.Expect(lines[1]).To(gm.ContainSubstring(
+        `"log_level":"INFO","message":"Baz"`,
+    ))
+    g.Expect(lines[2]).To(gm.ContainSubstring(
+        `"log_level":"WARN","message":"Qux"`,
+    ))
+    g.Expect(lines[3]).To(gm.ContainSubstring(
+        `"log_level":"WARN","message":"Notalevel: Quux"`,
+    ))
+}
+
+func TestParseLevelPrefix(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    level, message := parseLevelPrefix("[ TRACE ]  Foo", INFO)
+    g.Expect(level).To(gm.Equal(TRACE))
+    g.Expect(message).To(gm.Equal("Foo"))
+
+    level, message = parseLevelPrefix("err: Foo", INFO)
+    g.Expect(level).To(gm.Equal(ERROR))
+    g.Expect(message).To(gm.Equal("Foo"))
+
+    level, message = parseLevelPrefix("[Foo", INFO)
+    g.Expect(level).To(gm.Equal(INFO))
+    g.Expect(message).To(gm.Equal("[Foo"))
+
+    level, message = parseLevelPrefix("[]: Foo", INFO)
+    g.Expect(level).To(gm.Equal(INFO))
+    g.Expect(message).To(gm.Equal("[]: Foo"))
+}
+
+func TestRedirectStdLog(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder, previousBuilder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    originalWriter, originalPrefix, originalFlags :=
+        log.Writer(), log.Prefix(), log.Flags()
+    defer func() {
+        log.SetOutput(originalWriter)
+        log.SetPrefix(originalPrefix)
+        log.SetFlags(originalFlags)
+    }()
+    log.SetOutput(&previousBuilder)
+    log.SetPrefix("prefix ")
+    log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+    restore := RedirectStdLog(newLogger, INFO)
+    log.Print("Foo")
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `^{"timestamp":\d+,"log_level":"INFO","message":"Foo"}\n$`,
+    ))
+
+    restore()
+    log.Print("Bar")
+    g.Expect(builder.String()).ToNot(gm.ContainSubstring("Bar"))
+    g.Expect(log.Prefix()).To(gm.Equal("prefix "))
+    g.Expect(log.Flags()).To(gm.Equal(log.LstdFlags | log.Lshortfile))
+    g.Expect(previousBuilder.String()).To(gm.MatchRegexp(
+        `^prefix \S+ \S+ std_log_test\.go:\d+: Bar\n$`,
+    ))
+}
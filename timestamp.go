@@ -1,22 +1,101 @@
 package logging
 
 import (
-	"fmt"
-	"time"
+    "encoding/json"
+    "strconv"
+    "strings"
+    "time"
 )
 
-// timestamp is a time.Time that marshals to unix timestamp.
+// TimestampFormat is a representation of how a log's timestamp should be
+// output. Any value other than the ones defined below is treated as a custom
+// layout for time.Time.Format.
+type TimestampFormat string
+
+// Definition of the known TimestampFormats.
+const (
+    // DefaultTimestampFormat uses the default of whichever LogFormat is being
+    // used; unix seconds for JSON and a second-precision local time for the
+    // Standard formats.
+    DefaultTimestampFormat TimestampFormat = ""
+    UnixSeconds TimestampFormat = "unix"
+    UnixMillis TimestampFormat = "unixmilli"
+    UnixNanos TimestampFormat = "unixnano"
+    RFC3339 TimestampFormat = "rfc3339"
+    RFC3339Nano TimestampFormat = "rfc3339nano"
+)
+
+const standardTimestampLayout = "2006-01-02T15:04:05"
+
+// TimestampFormatFromString gets the TimestampFormat for the provided string.
+// Known format names are matched case-insensitively; anything else is assumed
+// to be a custom layout.
+func TimestampFormatFromString(format string) TimestampFormat {
+    switch lowerFormat := TimestampFormat(strings.ToLower(format)); lowerFormat {
+    case UnixSeconds, UnixMillis, UnixNanos, RFC3339, RFC3339Nano:
+        return lowerFormat
+    default:
+        return TimestampFormat(format)
+    }
+}
+
+// isNumeric is true for the formats which result in a number.
+func (self TimestampFormat) isNumeric() bool {
+    switch self {
+    case UnixSeconds, UnixMillis, UnixNanos:
+        return true
+    default:
+        return false
+    }
+}
+
+// Format formats the provided time according to this TimestampFormat.
+func (self TimestampFormat) Format(t time.Time) string {
+    switch self {
+    case DefaultTimestampFormat:
+        return t.Format(standardTimestampLayout)
+    case UnixSeconds:
+        return strconv.FormatInt(t.Unix(), 10)
+    case UnixMillis:
+        return strconv.FormatInt(t.UnixNano() / int64(time.Millisecond), 10)
+    case UnixNanos:
+        return strconv.FormatInt(t.UnixNano(), 10)
+    case RFC3339:
+        return t.Format(time.RFC3339)
+    case RFC3339Nano:
+        return t.Format(time.RFC3339Nano)
+    default:
+        return t.Format(string(self))
+    }
+}
+
+// timestamp is a time.Time that marshals according to a TimestampFormat.
 type timestamp struct {
-	time.Time
+    time.Time
+    format TimestampFormat
 }
 
-// MarshalJSON will marshal the timestamp into a unix timestamp.
+// withDefault replaces an unset format with the provided format.
+func (t timestamp) withDefault(format TimestampFormat) timestamp {
+    if t.format == DefaultTimestampFormat {
+        t.format = format
+    }
+
+    return t
+}
+
+// MarshalJSON will marshal the timestamp into a number for the unix formats
+// (unix seconds if unset) or a string otherwise.
 func (t timestamp) MarshalJSON() ([]byte, error) {
-	unixTimestamp := fmt.Sprintf("%v", t.Unix())
-	return []byte(unixTimestamp), nil
+    t = t.withDefault(UnixSeconds)
+    formatted := t.format.Format(t.Time)
+    if t.format.isNumeric() {
+        return []byte(formatted), nil
+    }
+
+    return json.Marshal(formatted)
 }
 
 func (t timestamp) String() string {
-    timeBytes := t.Format("2006-01-02T15:04:05")
-	return string(timeBytes)
+    return t.format.Format(t.Time)
 }
@@ -0,0 +1,90 @@
+/* #nosec G404 */
+package logging
+
+import (
+    "encoding/json"
+    "math/rand"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+
+    gm "github.com/onsi/gomega"
+)
+
+func TestTimestampFormats(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    testTime := time.Date(2019, 3, 9, 14, 59, 50, 123456789, time.UTC)
+
+    g.Expect(DefaultTimestampFormat.Format(testTime)).To(
+        gm.Equal("2019-03-09T14:59:50"),
+    )
+    g.Expect(UnixSeconds.Format(testTime)).To(gm.Equal("1552143590"))
+    g.Expect(UnixMillis.Format(testTime)).To(gm.Equal("1552143590123"))
+    g.Expect(UnixNanos.Format(testTime)).To(
+        gm.Equal("1552143590123456789"),
+    )
+    g.Expect(RFC3339.Format(testTime)).To(gm.Equal("2019-03-09T14:59:50Z"))
+    g.Expect(RFC3339Nano.Format(testTime)).To(
+        gm.Equal("2019-03-09T14:59:50.123456789Z"),
+    )
+    g.Expect(TimestampFormat("15:04").Format(testTime)).To(gm.Equal("14:59"))
+}
+
+func TestTimestampFormatFromString(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    g.Expect(TimestampFormatFromString("UnixMilli")).To(gm.Equal(UnixMillis))
+    g.Expect(TimestampFormatFromString("RFC3339")).To(gm.Equal(RFC3339))
+    g.Expect(TimestampFormatFromString("2006-01-02")).To(
+        gm.Equal(TimestampFormat("2006-01-02")),
+    )
+}
+
+func TestTimestampMarshalJSON(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    testTime := time.Date(2019, 3, 9, 14, 59, 50, 123456789, time.UTC)
+
+    result, err := json.Marshal(timestamp{testTime, DefaultTimestampFormat})
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(string(result)).To(gm.Equal("1552143590"))
+
+    result, err = json.Marshal(timestamp{testTime, UnixMillis})
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(string(result)).To(gm.Equal("1552143590123"))
+
+    result, err = json.Marshal(timestamp{testTime, RFC3339})
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    g.Expect(string(result)).To(gm.Equal(`"2019-03-09T14:59:50Z"`))
+}
+
+func TestLoggerTimestampFormat(t *testing.T) {
+    g := gm.NewGomegaWithT(t)
+
+    var builder strings.Builder
+    newLogger, err := NewLogger(
+        "test" + strconv.Itoa(rand.Int()),
+        WithLogWriters(&builder),
+        WithFormat(JSON),
+        WithTimestampFormat(RFC3339Nano),
+        WithUTC(true),
+    )
+    g.Expect(err).ToNot(gm.HaveOccurred())
+    defer newLogger.Close()
+
+    newLogger.Info("Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(
+        `{"timestamp":"[\d-]+T[\d:.]+Z","log_level":"INFO","message":"Foo"}`,
+    ))
+
+    builder.Reset()
+    newLogger.SetFormat(Standard)
+    newLogger.SetTimestampFormat(UnixMillis)
+
+    newLogger.Info("Foo")
+
+    g.Expect(builder.String()).To(gm.MatchRegexp(`^\d{13} INFO Foo\n$`))
+}